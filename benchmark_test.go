@@ -1,9 +1,15 @@
 package loads
 
 import (
+	"bytes"
 	_ "embed"
 	"encoding/json"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -16,7 +22,7 @@ var benchPathItem []byte
 //go:embed testdata/json/bench/footer.partial
 var benchFooter []byte
 
-func BenchmarkAnalyzed(b *testing.B) {
+func benchSpec() json.RawMessage {
 	d := make([]byte, 0, len(benchHeader)+1000*(len(benchPathItem)+20)+len(benchFooter))
 	d = append(d, benchHeader...)
 
@@ -29,7 +35,12 @@ func BenchmarkAnalyzed(b *testing.B) {
 	}
 
 	d = append(d, benchFooter...)
-	rm := json.RawMessage(d)
+
+	return json.RawMessage(d)
+}
+
+func BenchmarkAnalyzed(b *testing.B) {
+	rm := benchSpec()
 	b.ResetTimer()
 
 	for b.Loop() {
@@ -39,3 +50,90 @@ func BenchmarkAnalyzed(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkAnalyzedStream(b *testing.B) {
+	rm := benchSpec()
+	b.ResetTimer()
+
+	for b.Loop() {
+		_, err := AnalyzedStream(bytes.NewReader(rm), "")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkDocumentMemory reports the peak heap in use while loading a document ("peak-B"), besides
+// the memory allocated to load it (B/op).
+func BenchmarkDocumentMemory(b *testing.B) {
+	rm := benchSpec()
+
+	for name, load := range map[string]func() (*Document, error){
+		"Analyzed": func() (*Document, error) {
+			return Analyzed(bytes.Clone(rm), "") // the loaded bytes are part of the cost
+		},
+		"AnalyzedStream": func() (*Document, error) {
+			return AnalyzedStream(bytes.NewReader(rm), "")
+		},
+	} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			var peak uint64
+			for b.Loop() {
+				peak += peakHeap(b, load)
+			}
+
+			b.ReportMetric(float64(peak)/float64(b.N), "peak-B")
+		})
+	}
+}
+
+// peakHeap returns the peak of the heap in use while load runs, above the heap in use before.
+//
+// The heap is sampled while load runs. The collector is set to run at every 1% of heap growth, so
+// that the heap in use stays close to the live heap.
+func peakHeap(b *testing.B, load func() (*Document, error)) uint64 {
+	b.Helper()
+	defer debug.SetGCPercent(debug.SetGCPercent(1))
+
+	heap := func() uint64 {
+		sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+		metrics.Read(sample)
+
+		return sample[0].Value.Uint64()
+	}
+
+	runtime.GC()
+	base := heap()
+
+	var (
+		peak atomic.Uint64
+		done = make(chan struct{})
+		wg   sync.WaitGroup
+	)
+	wg.Go(func() {
+		for {
+			if h := heap(); h > peak.Load() {
+				peak.Store(h)
+			}
+
+			select {
+			case <-done:
+				return
+			default:
+				runtime.Gosched()
+			}
+		}
+	})
+
+	document, err := load()
+	close(done)
+	wg.Wait()
+	if err != nil {
+		b.Fatal(err)
+	}
+	runtime.KeepAlive(document)
+
+	return peak.Load() - min(base, peak.Load())
+}
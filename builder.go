@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// DocumentBuilder builds a spec programmatically, with a fluent API:
+//
+//	document, err := loads.NewDocumentBuilder().
+//		Info("petstore", "1.0.0").
+//		BasePath("/api").
+//		Definition("Pet", *spec.StringProperty()).
+//		Operation(http.MethodGet, "/pets", spec.NewOperation("listPets").
+//			RespondsWith(http.StatusOK, spec.NewResponse().WithSchema(spec.RefSchema("#/definitions/Pet")))).
+//		Build()
+//
+// Errors, such as an unsupported HTTP method, are reported by [DocumentBuilder.Build].
+// A builder may be reused: later calls to the builder do not affect the documents it already built.
+// The values passed to the builder (schemas, parameters, operations, ...) are shared with the documents
+// it builds, and must not be altered afterwards.
+type DocumentBuilder struct {
+	spec *spec.Swagger
+	err  error
+}
+
+// NewDocumentBuilder starts a swagger 2.0 spec, with no paths.
+func NewDocumentBuilder() *DocumentBuilder {
+	return &DocumentBuilder{
+		spec: &spec.Swagger{
+			SwaggerProps: spec.SwaggerProps{
+				Swagger: "2.0",
+				Info:    &spec.Info{},
+				Paths:   &spec.Paths{Paths: make(map[string]spec.PathItem)},
+			},
+		},
+	}
+}
+
+// Info sets the title and version of the API.
+func (b *DocumentBuilder) Info(title, version string) *DocumentBuilder {
+	b.spec.Info.Title = title
+	b.spec.Info.Version = version
+
+	return b
+}
+
+// Host sets the host serving the API.
+func (b *DocumentBuilder) Host(host string) *DocumentBuilder {
+	b.spec.Host = host
+
+	return b
+}
+
+// BasePath sets the base path of the API.
+func (b *DocumentBuilder) BasePath(basePath string) *DocumentBuilder {
+	b.spec.BasePath = basePath
+
+	return b
+}
+
+// Schemes sets the transfer protocols of the API, e.g. "https".
+func (b *DocumentBuilder) Schemes(schemes ...string) *DocumentBuilder {
+	b.spec.Schemes = schemes
+
+	return b
+}
+
+// Consumes sets the MIME types consumed by the API.
+func (b *DocumentBuilder) Consumes(mediaTypes ...string) *DocumentBuilder {
+	b.spec.Consumes = mediaTypes
+
+	return b
+}
+
+// Produces sets the MIME types produced by the API.
+func (b *DocumentBuilder) Produces(mediaTypes ...string) *DocumentBuilder {
+	b.spec.Produces = mediaTypes
+
+	return b
+}
+
+// Definition adds a model under "#/definitions/{name}".
+func (b *DocumentBuilder) Definition(name string, schema spec.Schema) *DocumentBuilder {
+	if b.spec.Definitions == nil {
+		b.spec.Definitions = make(spec.Definitions)
+	}
+	b.spec.Definitions[name] = schema
+
+	return b
+}
+
+// Parameter adds a shared parameter under "#/parameters/{name}".
+func (b *DocumentBuilder) Parameter(name string, parameter spec.Parameter) *DocumentBuilder {
+	if b.spec.Parameters == nil {
+		b.spec.Parameters = make(map[string]spec.Parameter)
+	}
+	b.spec.Parameters[name] = parameter
+
+	return b
+}
+
+// Response adds a shared response under "#/responses/{name}".
+func (b *DocumentBuilder) Response(name string, response spec.Response) *DocumentBuilder {
+	if b.spec.Responses == nil {
+		b.spec.Responses = make(map[string]spec.Response)
+	}
+	b.spec.Responses[name] = response
+
+	return b
+}
+
+// SecurityDefinition adds a security scheme under "#/securityDefinitions/{name}".
+func (b *DocumentBuilder) SecurityDefinition(name string, scheme *spec.SecurityScheme) *DocumentBuilder {
+	if b.spec.SecurityDefinitions == nil {
+		b.spec.SecurityDefinitions = make(spec.SecurityDefinitions)
+	}
+	b.spec.SecurityDefinitions[name] = scheme
+
+	return b
+}
+
+// Path sets the path item for path, replacing all its operations.
+func (b *DocumentBuilder) Path(path string, item spec.PathItem) *DocumentBuilder {
+	b.spec.Paths.Paths[path] = item
+
+	return b
+}
+
+// Operation sets the operation for the given HTTP method (e.g. [http.MethodGet]) and path.
+func (b *DocumentBuilder) Operation(method, path string, operation *spec.Operation) *DocumentBuilder {
+	item := b.spec.Paths.Paths[path]
+
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		item.Get = operation
+	case http.MethodPut:
+		item.Put = operation
+	case http.MethodPost:
+		item.Post = operation
+	case http.MethodDelete:
+		item.Delete = operation
+	case http.MethodOptions:
+		item.Options = operation
+	case http.MethodHead:
+		item.Head = operation
+	case http.MethodPatch:
+		item.Patch = operation
+	default:
+		if b.err == nil {
+			b.err = fmt.Errorf("%w: unsupported HTTP method %q for path %q", ErrLoads, method, path)
+		}
+
+		return b
+	}
+
+	b.spec.Paths.Paths[path] = item
+
+	return b
+}
+
+// Build creates a new analyzed [Document] for the spec, like [NewDocument], with the loader
+// configured by opts to resolve its references.
+func (b *DocumentBuilder) Build(opts ...LoaderOption) (*Document, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return NewDocument(b.snapshot(), opts...)
+}
+
+// snapshot returns a copy of the spec, which later calls to the builder leave untouched.
+//
+// The builder only ever sets fields and map entries, so copying the maps it writes to is enough:
+// there is no need for a deep copy.
+func (b *DocumentBuilder) snapshot() *spec.Swagger {
+	swspec := *b.spec
+	info := *b.spec.Info
+	swspec.Info = &info
+	swspec.Paths = &spec.Paths{
+		VendorExtensible: b.spec.Paths.VendorExtensible,
+		Paths:            maps.Clone(b.spec.Paths.Paths),
+	}
+	swspec.Definitions = maps.Clone(b.spec.Definitions)
+	swspec.Parameters = maps.Clone(b.spec.Parameters)
+	swspec.Responses = maps.Clone(b.spec.Responses)
+	swspec.SecurityDefinitions = maps.Clone(b.spec.SecurityDefinitions)
+
+	return &swspec
+}
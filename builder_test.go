@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestNewDocument(t *testing.T) {
+	t.Run("should analyze a spec built programmatically", func(t *testing.T) {
+		var sp spec.Swagger
+		require.NoError(t, json.Unmarshal(petStoreJSON, &sp))
+
+		document, err := NewDocument(&sp)
+		require.NoError(t, err)
+
+		assert.EqualT(t, "petstore.swagger.wordnik.com", document.Host())
+		assert.Same(t, &sp, document.Spec())
+		assert.NotSame(t, document.Spec(), document.OrigSpec())
+		assert.JSONMarshalAsT(t, document.Raw(), document.Spec())
+		assert.JSONMarshalAsT(t, document.SourceRaw(), document.OrigSpec())
+		assert.NotEmpty(t, document.Analyzer.OperationIDs())
+	})
+
+	t.Run("should keep the loader configuration", func(t *testing.T) {
+		sp := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Info:  &spec.Info{InfoProps: spec.InfoProps{Title: "t", Version: "1"}},
+			Paths: &spec.Paths{},
+			Definitions: spec.Definitions{
+				"b": *spec.RefSchema(mirroredPrefix + "test3-ter-model-schema.json#/definitions/b"),
+			},
+		}}
+
+		document, err := NewDocument(sp, WithRefMapping(mirroredPrefix, "testdata/yaml/swagger"), WithOffline())
+		require.NoError(t, err)
+		assert.EqualT(t, "2.0", document.Version())
+		assert.EqualT(t, "", sp.Swagger, "the caller's spec should not be altered")
+
+		expanded, err := document.Expanded()
+		require.NoError(t, err)
+		b := expanded.Spec().Definitions["b"]
+		assert.True(t, b.Type.Contains("array"))
+	})
+
+	t.Run("should reject invalid specs", func(t *testing.T) {
+		_, err := NewDocument(nil)
+		require.ErrorIs(t, err, ErrLoads)
+
+		_, err = NewDocument(&spec.Swagger{SwaggerProps: spec.SwaggerProps{Swagger: "3.0"}})
+		require.ErrorIs(t, err, ErrLoads)
+	})
+}
+
+func TestDocumentBuilder(t *testing.T) {
+	t.Run("should build an analyzed document", func(t *testing.T) {
+		builder := NewDocumentBuilder().
+			Info("petstore", "1.0.0").
+			Host("petstore.example.com").
+			BasePath("/api").
+			Schemes("https").
+			Consumes("application/json").
+			Produces("application/json").
+			Definition("Pet", *spec.MapProperty(spec.StringProperty())).
+			Parameter("limit", *spec.QueryParam("limit").Typed("integer", "int32")).
+			Response("NotFound", *spec.NewResponse().WithDescription("not found")).
+			SecurityDefinition("key", spec.APIKeyAuth("X-API-Key", "header")).
+			Operation(http.MethodGet, "/pets", spec.NewOperation("listPets").
+				AddParam(spec.ParamRef("#/parameters/limit")).
+				RespondsWith(http.StatusOK, spec.NewResponse().WithDescription("pets").
+					WithSchema(spec.ArrayProperty(spec.RefSchema("#/definitions/Pet")))).
+				RespondsWith(http.StatusNotFound, spec.ResponseRef("#/responses/NotFound"))).
+			Operation("post", "/pets", spec.NewOperation("createPet"))
+
+		document, err := builder.Build()
+		require.NoError(t, err)
+
+		assert.EqualT(t, "/api", document.BasePath())
+		op, ok := document.Analyzer.OperationFor(http.MethodGet, "/pets")
+		require.True(t, ok)
+		assert.EqualT(t, "listPets", op.ID)
+		_, ok = document.Analyzer.OperationFor(http.MethodPost, "/pets")
+		assert.True(t, ok)
+		assert.Len(t, document.Analyzer.AllRefs(), 3)
+		assert.Contains(t, document.Spec().SecurityDefinitions, "key")
+
+		expanded, err := document.Expanded()
+		require.NoError(t, err)
+		assert.NotContains(t, string(expanded.Raw()), "$ref")
+
+		// the builder may be reused without affecting documents already built
+		again, err := builder.Host("other.example.com").
+			Info("other", "2.0.0").
+			Definition("Owner", *spec.StringProperty()).
+			Operation(http.MethodDelete, "/pets", spec.NewOperation("deletePets")).
+			Build()
+		require.NoError(t, err)
+		assert.EqualT(t, "other.example.com", again.Host())
+		assert.Contains(t, again.Spec().Definitions, "Owner")
+		assert.EqualT(t, "petstore.example.com", document.Host())
+		assert.EqualT(t, "petstore", document.Spec().Info.Title)
+		assert.NotContains(t, document.Spec().Definitions, "Owner")
+		assert.Nil(t, document.Spec().Paths.Paths["/pets"].Delete)
+	})
+
+	t.Run("should report unsupported methods", func(t *testing.T) {
+		_, err := NewDocumentBuilder().
+			Operation("TRACE", "/pets", spec.NewOperation("tracePets")).
+			Build()
+		require.ErrorIs(t, err, ErrLoads)
+	})
+}
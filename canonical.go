@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Canonical returns the canonical JSON of the document: two documents that differ only by their
+// formatting have the same canonical JSON.
+//
+// The canonical JSON is compact, with the keys of objects sorted, numbers written in a single form
+// (e.g. 1.0 and 1e0 are both written 1) and no HTML escaping. "$ref"s are normalized as well:
+// references to the document itself by its location become local references (e.g. "spec.json#/definitions/Pet"
+// from spec.json becomes "#/definitions/Pet"), JSON pointers are unescaped from percent-encoding, and the
+// paths of references to other documents are cleaned (e.g. "./models/../models/pet.json" becomes
+// "models/pet.json").
+func (d *Document) Canonical() (json.RawMessage, error) {
+	return canonicalJSON(d.Raw(), d.specFilePath)
+}
+
+// FingerprintOption configures [Document.Fingerprint].
+type FingerprintOption func(*fingerprintOptions)
+
+type fingerprintOptions struct {
+	dependencies bool
+}
+
+// FingerprintDependencies makes the fingerprint cover the documents the spec depends on, directly or
+// transitively, as loaded by the document's loader.
+func FingerprintDependencies() FingerprintOption {
+	return func(o *fingerprintOptions) {
+		o.dependencies = true
+	}
+}
+
+// Fingerprint returns a stable hash of the document: the hex-encoded SHA-256 of its canonical JSON
+// (see [Document.Canonical]).
+//
+// By default, only the document itself is covered. With [FingerprintDependencies], the documents it
+// references are loaded and covered as well, by their location relative to the document, so that the
+// fingerprint does not depend on where a local spec tree is stored.
+func (d *Document) Fingerprint(opts ...FingerprintOption) (string, error) {
+	var o fingerprintOptions
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	canonical, err := d.Canonical()
+	if err != nil {
+		return "", err
+	}
+
+	if !o.dependencies {
+		return hashOf(canonical), nil
+	}
+
+	var buf bytes.Buffer
+	buf.Write(canonical)
+
+	dependencies, err := d.canonicalDependencies()
+	if err != nil {
+		return "", err
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(dependencies)) {
+		fmt.Fprintf(&buf, "\n%s\n", name)
+		buf.Write(dependencies[name])
+	}
+
+	return hashOf(buf.Bytes()), nil
+}
+
+// canonicalDependencies loads the documents the spec depends on, and returns their canonical JSON
+// by their location relative to the document.
+func (d *Document) canonicalDependencies() (map[string]json.RawMessage, error) {
+	rootKey := lockKey(d.specFilePath)
+	dependencies := make(map[string]json.RawMessage)
+
+	err := d.walkDependencies(func(location string, doc fetchedDocument) error {
+		canonical, err := canonicalJSON(doc.json, location)
+		if err != nil {
+			return err
+		}
+		dependencies[dependencyName(rootKey, lockKey(location))] = canonical
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dependencies, nil
+}
+
+// dependencyName designates a dependency independently of where the spec is stored: remote
+// documents by their URL, local ones relative to the root document.
+func dependencyName(rootKey, key string) string {
+	if isRemote(key) || isRemote(rootKey) {
+		return key
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(rootKey)), filepath.FromSlash(key))
+	if err != nil {
+		return key
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// canonicalJSON writes the canonical form of the JSON document data, found at location.
+func canonicalJSON(data json.RawMessage, location string) (json.RawMessage, error) {
+	node, err := decodeJSON(data)
+	if err != nil {
+		return nil, errLoads(err)
+	}
+
+	walkRefs(node, func(holder map[string]any, ref string) {
+		holder[refKey] = canonicalRef(ref, location)
+	})
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(canonicalNumbers(node)); err != nil {
+		return nil, errLoads(err)
+	}
+
+	return json.RawMessage(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), nil
+}
+
+// canonicalRef normalizes a "$ref" found in the document at location.
+func canonicalRef(ref, location string) string {
+	document, fragment := splitRef(ref)
+
+	tokens := strings.Split(decodeFragment(fragment), "/")
+	for i, token := range tokens {
+		tokens[i] = escapePointerToken(unescapePointerToken(token))
+	}
+	fragment = strings.Join(tokens, "/")
+
+	switch {
+	case document == "":
+	case location != "" && lockKey(resolveDocument(location, document)) == lockKey(location):
+		document = ""
+	case !isRemote(document):
+		document = path.Clean(filepath.ToSlash(document))
+	}
+
+	if fragment == "" && document != "" {
+		return document
+	}
+
+	return document + "#" + fragment
+}
+
+// canonicalNumbers writes every number of a generic JSON tree in a single form.
+func canonicalNumbers(node any) any {
+	switch n := node.(type) {
+	case map[string]any:
+		for k, v := range n {
+			n[k] = canonicalNumbers(v)
+		}
+
+		return n
+	case []any:
+		for i, v := range n {
+			n[i] = canonicalNumbers(v)
+		}
+
+		return n
+	case json.Number:
+		return canonicalNumber(n)
+	default:
+		return n
+	}
+}
+
+// canonicalNumber writes integers in decimal form, without loss of precision, and other numbers in their
+// shortest form.
+func canonicalNumber(n json.Number) json.Number {
+	s := string(n)
+	if digits := strings.TrimPrefix(s, "-"); digits != "" && strings.Trim(digits, "0123456789") == "" {
+		digits = strings.TrimLeft(digits, "0")
+		if digits == "" {
+			return "0"
+		}
+		if strings.HasPrefix(s, "-") {
+			return json.Number("-" + digits)
+		}
+
+		return json.Number(digits)
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return n
+	}
+
+	const maxPlainInteger = 1e21
+	if f == math.Trunc(f) && math.Abs(f) < maxPlainInteger {
+		if f == 0 {
+			return "0"
+		}
+
+		return json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+	}
+
+	return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+}
@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestCanonical(t *testing.T) {
+	jsonDoc, err := Analyzed([]byte(`{
+  "swagger": "2.0",
+  "info": {"version": "1.0", "title": "<pets>"},
+  "paths": {},
+  "definitions": {
+    "Pet": {"type": "number", "maximum": 10.0, "minimum": -0, "multipleOf": 1.5e0}
+  }
+}`), "")
+	require.NoError(t, err)
+
+	yamlDoc, err := Analyzed([]byte(`
+swagger: "2.0"
+definitions:
+  Pet:
+    multipleOf: 1.5
+    minimum: 0
+    maximum: 1e1
+    type: number
+paths: {}
+info:
+  title: <pets>
+  version: "1.0"
+`), "")
+	require.NoError(t, err)
+
+	t.Run("should not depend on the formatting", func(t *testing.T) {
+		canonical, err := jsonDoc.Canonical()
+		require.NoError(t, err)
+		assert.EqualT(t,
+			`{"definitions":{"Pet":{"maximum":10,"minimum":0,"multipleOf":1.5,"type":"number"}},"info":{"title":"<pets>","version":"1.0"},"paths":{},"swagger":"2.0"}`,
+			string(canonical),
+		)
+
+		other, err := yamlDoc.Canonical()
+		require.NoError(t, err)
+		assert.EqualT(t, string(canonical), string(other))
+	})
+
+	t.Run("should normalize references", func(t *testing.T) {
+		document, err := Analyzed([]byte(`{
+  "swagger": "2.0",
+  "definitions": {
+    "a": {"$ref": "spec.json#/definitions/P%65t"},
+    "b": {"$ref": "./models/../models/pet.json#/definitions/Pet"},
+    "c": {"$ref": "https://example.com/pet.json#"},
+    "d": {"$ref": "#%2Fdefinitions%2Fa%7E1b"}
+  }
+}`), "")
+		require.NoError(t, err)
+		document.specFilePath = filepath.Join("specs", "spec.json")
+
+		canonical, err := document.Canonical()
+		require.NoError(t, err)
+		assert.JSONEqT(t, `{
+  "swagger": "2.0",
+  "definitions": {
+    "a": {"$ref": "#/definitions/Pet"},
+    "b": {"$ref": "models/pet.json#/definitions/Pet"},
+    "c": {"$ref": "https://example.com/pet.json"},
+    "d": {"$ref": "#/definitions/a~1b"}
+  }
+}`, string(canonical))
+	})
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Run("should not depend on the formatting", func(t *testing.T) {
+		a, err := Analyzed([]byte(`{"swagger": "2.0", "paths": {}}`), "")
+		require.NoError(t, err)
+
+		b, err := Analyzed([]byte("paths: {}\nswagger: \"2.0\"\n"), "")
+		require.NoError(t, err)
+
+		fingerprint, err := a.Fingerprint()
+		require.NoError(t, err)
+		assert.Len(t, fingerprint, 64)
+
+		other, err := b.Fingerprint()
+		require.NoError(t, err)
+		assert.EqualT(t, fingerprint, other)
+	})
+
+	t.Run("should optionally cover the dependencies", func(t *testing.T) {
+		copyTree := func() string {
+			dir := filepath.Join(t.TempDir(), "swagger")
+			require.NoError(t, os.CopyFS(dir, os.DirFS("testdata/yaml/swagger")))
+
+			return dir
+		}
+		first, second := copyTree(), copyTree()
+
+		fingerprints := func(dir string) (string, string) {
+			document, err := Spec(filepath.Join(dir, "spec.yml"))
+			require.NoError(t, err)
+
+			own, err := document.Fingerprint()
+			require.NoError(t, err)
+
+			all, err := document.Fingerprint(FingerprintDependencies())
+			require.NoError(t, err)
+
+			return own, all
+		}
+
+		own, all := fingerprints(first)
+		assert.NotEqual(t, own, all)
+
+		otherOwn, otherAll := fingerprints(second)
+		assert.EqualT(t, own, otherOwn)
+		assert.EqualT(t, all, otherAll, "the fingerprint should not depend on where the tree is stored")
+
+		dependency := filepath.Join(second, "test3-ter-model-schema.json")
+		data, err := os.ReadFile(dependency)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(dependency, append([]byte("\n\n"), data...), 0o600))
+
+		otherOwn, otherAll = fingerprints(second)
+		assert.EqualT(t, own, otherOwn)
+		assert.EqualT(t, all, otherAll, "the fingerprint should not depend on the formatting of a dependency")
+
+		require.NoError(t, os.WriteFile(dependency, []byte(`{"definitions": {"b": {"type": "string"}}}`), 0o600))
+
+		otherOwn, otherAll = fingerprints(second)
+		assert.EqualT(t, own, otherOwn)
+		assert.NotEqual(t, all, otherAll)
+	})
+}
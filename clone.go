@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/go-openapi/analysis"
+	"github.com/go-openapi/spec"
+)
+
+// Clone returns an independent deep copy of the document: its spec, original spec, raw bytes,
+// analyzer and loader configuration are all copies.
+//
+// The clone may be altered freely, e.g. through [Document.Spec], without affecting the receiver or
+// any other document derived from it.
+func (d *Document) Clone() *Document {
+	dd := &Document{
+		specFilePath: d.specFilePath,
+		schema:       d.schema, // the swagger 2.0 meta-schema is shared
+		raw:          slices.Clone(d.raw),
+		specRaw:      slices.Clone(d.specRaw),
+		pathLoader:   d.pathLoader.clone(),
+	}
+
+	if d.spec != nil {
+		dd.spec = copySpec(d.spec)
+	}
+
+	if d.source != nil {
+		source := &docSource{raw: slices.Clone(d.SourceRaw()), fetched: slices.Clone(d.source.fetched)}
+		if orig := d.OrigSpec(); orig != nil {
+			source.spec = copySpec(orig)
+		}
+		dd.source = source
+	}
+
+	if d.Analyzer != nil && dd.spec != nil {
+		dd.Analyzer = analysis.New(dd.spec)
+	}
+
+	return dd
+}
+
+// Modified applies fn to a copy of the spec and returns a new [Document] for the result.
+//
+// This is the copy-on-write way to alter a document: the receiver, which may be shared by other
+// goroutines, is left untouched. Like with any derivation, the new document keeps the source and
+// the loader configuration of the receiver, and its raw bytes and analyzer reflect the modified spec.
+//
+// If fn returns an error, Modified returns this error and no document.
+func (d *Document) Modified(fn func(*spec.Swagger) error) (*Document, error) {
+	swspec := copySpec(d.spec)
+	if err := fn(swspec); err != nil {
+		return nil, err
+	}
+
+	return d.derive(swspec), nil
+}
+
+// copySpec returns a deep copy of src.
+//
+// The copy is a JSON round trip: unlike [cloneSpec], it preserves pointers to zero values, e.g. "minimum": 0.
+func copySpec(src *spec.Swagger) *spec.Swagger {
+	var dst spec.Swagger
+	b, _ := json.Marshal(src)   //nolint:errchkjson // the spec always marshals to JSON
+	_ = json.Unmarshal(b, &dst) // the spec always unmarshals from its own JSON
+
+	return &dst
+}
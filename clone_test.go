@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestClone(t *testing.T) {
+	document, err := Spec("testdata/yaml/swagger/spec.yml", WithOffline())
+	require.NoError(t, err)
+	raw := string(document.Raw())
+
+	clone := document.Clone()
+	require.NotSame(t, document, clone)
+	assert.NotSame(t, document.Spec(), clone.Spec())
+	assert.NotSame(t, document.OrigSpec(), clone.OrigSpec())
+	assert.NotSame(t, document.Analyzer, clone.Analyzer)
+	assert.NotSame(t, document.pathLoader, clone.pathLoader)
+	assert.Equal(t, document.Raw(), clone.Raw())
+	assert.Equal(t, document.SourceRaw(), clone.SourceRaw())
+	assertSameJSON(t, document.Spec(), clone.Spec())
+	assertSameJSON(t, document.OrigSpec(), clone.OrigSpec())
+	assert.EqualT(t, document.SpecFilePath(), clone.SpecFilePath())
+	assert.Equal(t, document.LoaderSettings().Offline, clone.LoaderSettings().Offline)
+
+	// altering the clone leaves the receiver untouched
+	clone.Spec().Host = "clone.example.com"
+	clone.Spec().Definitions["a"] = spec.Schema{}
+	clone.OrigSpec().Definitions["b"] = spec.Schema{}
+	clone.Raw()[0] = ' '
+	clone.pathLoader.refs.offline = false
+
+	assert.EqualT(t, "api.example.com", document.Host())
+	a := document.Spec().Definitions["a"]
+	assert.True(t, a.Type.Contains("string"))
+	b := document.OrigSpec().Definitions["b"]
+	assert.NotEmpty(t, b.Ref.String())
+	assert.EqualT(t, raw, string(document.Raw()))
+	assert.True(t, document.LoaderSettings().Offline)
+
+	expanded, err := document.Clone().Expanded()
+	require.NoError(t, err)
+	assert.JSONMarshalAsT(t, cascadeRefExpanded, expanded.Spec())
+}
+
+func TestModified(t *testing.T) {
+	document, err := Embedded(petStoreJSON, petStoreJSON)
+	require.NoError(t, err)
+
+	t.Run("should alter a copy", func(t *testing.T) {
+		modified, err := document.Modified(func(sp *spec.Swagger) error {
+			sp.Host = "modified.example.com"
+			delete(sp.Definitions, "Pet")
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.EqualT(t, "modified.example.com", modified.Host())
+		assert.StringContainsT(t, string(modified.Raw()), "modified.example.com")
+		assert.NotContains(t, modified.Spec().Definitions, "Pet")
+		assert.Equal(t, document.SourceRaw(), modified.SourceRaw())
+
+		assert.EqualT(t, "petstore.swagger.wordnik.com", document.Host())
+		assert.Contains(t, document.Spec().Definitions, "Pet")
+	})
+
+	t.Run("should report the error of the modification", func(t *testing.T) {
+		modified, err := document.Modified(func(*spec.Swagger) error {
+			return errTest
+		})
+		require.ErrorIs(t, err, errTest)
+		assert.Nil(t, modified)
+	})
+
+	t.Run("ResetDefinitions should not share models with the original spec", func(t *testing.T) {
+		reset := document.ResetDefinitions()
+		pet := reset.Spec().Definitions["Pet"]
+		pet.Properties["id"] = spec.Schema{}
+
+		original := document.OrigSpec().Definitions["Pet"]
+		id := original.Properties["id"]
+		require.NotNil(t, id.Minimum)
+		assert.EqualT(t, 0.0, *id.Minimum)
+	})
+}
+
+func TestCopyOnWriteConcurrency(t *testing.T) {
+	document, err := Spec("testdata/yaml/swagger/spec.yml")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			modified, err := document.Modified(func(sp *spec.Swagger) error {
+				sp.Host = "host" + strconv.Itoa(i)
+
+				return nil
+			})
+			assert.NoError(t, err)
+			assert.EqualT(t, "host"+strconv.Itoa(i), modified.Host())
+
+			reset := document.ResetDefinitions()
+			reset.Spec().Definitions["c"+strconv.Itoa(i)] = spec.Schema{}
+
+			clone := document.Clone()
+			clone.Spec().Host = "clone" + strconv.Itoa(i)
+		})
+	}
+	wg.Wait()
+
+	assert.EqualT(t, "api.example.com", document.Host())
+	assert.Len(t, document.Spec().Definitions, 2)
+}
+
+func assertSameJSON(t *testing.T, expected, actual any) {
+	t.Helper()
+
+	b, err := json.Marshal(expected)
+	require.NoError(t, err)
+	assert.JSONMarshalAsT(t, b, actual)
+}
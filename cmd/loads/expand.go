@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-openapi/swag/loading"
+	yaml "go.yaml.in/yaml/v3"
+
+	"github.com/go-openapi/loads"
+)
+
+// Output formats, for the -format flag.
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+// outputFlags are the flags shared by the commands that write a spec.
+type outputFlags struct {
+	out    string
+	format string
+}
+
+func (f *outputFlags) register(fs *flag.FlagSet, format string) {
+	fs.StringVar(&f.out, "o", "", "output file (default: standard output)")
+	fs.StringVar(&f.format, "format", format, "output format: "+formatJSON+" or "+formatYAML)
+}
+
+// write writes the document in the configured format.
+func (f *outputFlags) write(doc *loads.Document, stdout io.Writer) error {
+	var data []byte
+	switch f.format {
+	case formatJSON:
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, doc.Raw(), "", "  "); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		data = buf.Bytes()
+	case formatYAML:
+		var err error
+		if data, err = toYAML(doc.Raw()); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: invalid format %q", errInvalidFlags, f.format)
+	}
+
+	if f.out == "" {
+		_, err := stdout.Write(data)
+
+		return err
+	}
+
+	return os.WriteFile(f.out, data, 0o600)
+}
+
+// toYAML converts JSON to block-style YAML, preserving the order of keys.
+func toYAML(data json.RawMessage) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil { // JSON is YAML, in flow style
+		return nil, err
+	}
+	blockStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// blockStyle resets the style of the nodes, so that they are written in block style, and strings
+// are quoted only when needed.
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+func runExpand(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("expand", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		lf loadFlags
+		of outputFlags
+	)
+	lf.register(fs)
+	of.register(fs, formatJSON)
+
+	path, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	doc, err := lf.load(path)
+	if err != nil {
+		return err
+	}
+
+	expanded, err := doc.Expanded()
+	if err != nil {
+		return err
+	}
+
+	return of.write(expanded, stdout)
+}
+
+func runBundle(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("bundle", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		lf loadFlags
+		of outputFlags
+	)
+	lf.register(fs)
+	of.register(fs, formatJSON)
+
+	path, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	doc, err := lf.load(path)
+	if err != nil {
+		return err
+	}
+
+	bundled, err := doc.ExpandedPartial(loads.ExpandExternalOnly())
+	if err != nil {
+		return err
+	}
+
+	return of.write(bundled, stdout)
+}
+
+func runConvert(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		lf loadFlags
+		of outputFlags
+	)
+	lf.register(fs)
+	of.register(fs, "")
+	fs.Lookup("format").Usage += " (default: yaml for a JSON spec, json for a YAML spec)"
+
+	path, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	doc, err := lf.load(path)
+	if err != nil {
+		return err
+	}
+
+	if of.format == "" {
+		of.format = formatYAML
+		if loading.YAMLMatcher(path) {
+			of.format = formatJSON
+		}
+	}
+
+	return of.write(doc, stdout)
+}
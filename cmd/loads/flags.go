@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-openapi/swag/loading"
+
+	"github.com/go-openapi/loads"
+)
+
+// Network policies, for the -network flag.
+const (
+	networkPublic  = "public"
+	networkOffline = "offline"
+	networkAny     = "any"
+)
+
+// loadFlags are the flags shared by the commands that load a spec: they configure where the spec and
+// its references may be loaded from.
+type loadFlags struct {
+	roots    listFlag
+	network  networkFlag
+	offline  bool
+	mappings mappingFlag
+}
+
+func (f *loadFlags) register(fs *flag.FlagSet) {
+	fs.Var(&f.roots, "root", "confine local reads to a directory (repeatable): without it, local reads are not confined")
+	f.network = networkPublic
+	fs.Var(&f.network, "network",
+		"network policy for remote references: "+networkPublic+" (public addresses only), "+
+			networkOffline+" (no network access) or "+networkAny)
+	fs.BoolVar(&f.offline, "offline", false, "shorthand for -network "+networkOffline)
+	fs.Var(&f.mappings, "map", "map a URL prefix to a local directory, as prefix=dir (repeatable)")
+}
+
+// options returns the loader options that enforce the flags.
+func (f *loadFlags) options() ([]loads.LoaderOption, error) {
+	network := string(f.network)
+	if f.offline {
+		network = networkOffline
+	}
+
+	opts := f.mappings.options()
+	switch network {
+	case networkPublic:
+		if len(f.roots) == 0 {
+			opts = append(opts, loads.WithLoadingOptions(loading.WithHTTPClient(loads.RestrictedHTTPClient())))
+		}
+	case networkOffline:
+		opts = append(opts, loads.WithOffline())
+	case networkAny:
+		if len(f.roots) > 0 {
+			return nil, fmt.Errorf("%w: -network %s cannot be combined with -root", errInvalidFlags, networkAny)
+		}
+	}
+
+	if len(f.roots) > 0 {
+		// the restricted loaders also fetch remote documents with the restricted client
+		opts = append(opts, loads.WithDocLoaderMatches(loads.RestrictedRootsLoaders(f.roots, nil)...))
+	}
+
+	return opts, nil
+}
+
+// load loads the spec at path, as configured by the flags.
+func (f *loadFlags) load(path string) (*loads.Document, error) {
+	opts, err := f.options()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(f.roots) > 0 && isLocalPath(path) {
+		// the roots confine absolute paths: a relative one would be resolved against each root
+		if path, err = filepath.Abs(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return loads.Spec(path, opts...)
+}
+
+// isLocalPath tells if path designates a local file by its path rather than by a URL. Like the loads
+// package does, a one-letter scheme is taken as a Windows drive letter.
+func isLocalPath(path string) bool {
+	u, err := url.Parse(path)
+
+	return err != nil || len(u.Scheme) <= 1
+}
+
+// networkFlag is a network policy.
+type networkFlag string
+
+func (n *networkFlag) String() string {
+	return string(*n)
+}
+
+func (n *networkFlag) Set(value string) error {
+	switch value {
+	case networkPublic, networkOffline, networkAny:
+		*n = networkFlag(value)
+
+		return nil
+	default:
+		return fmt.Errorf("invalid network policy %q: %w", value, errUsage)
+	}
+}
+
+// listFlag collects the values of a repeated flag.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+
+	return nil
+}
+
+// mappingFlag collects repeated "-map prefix=dir" flags.
+type mappingFlag []loads.RefMapping
+
+func (m *mappingFlag) String() string {
+	parts := make([]string, 0, len(*m))
+	for _, mapping := range *m {
+		parts = append(parts, mapping.Prefix+"="+mapping.Dir)
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func (m *mappingFlag) Set(value string) error {
+	prefix, dir, ok := strings.Cut(value, "=")
+	if !ok || prefix == "" || dir == "" {
+		return fmt.Errorf("invalid mapping %q: expected prefix=dir: %w", value, errUsage)
+	}
+
+	*m = append(*m, loads.RefMapping{Prefix: prefix, Dir: dir})
+
+	return nil
+}
+
+func (m mappingFlag) options() []loads.LoaderOption {
+	opts := make([]loads.LoaderOption, 0, len(m))
+	for _, mapping := range m {
+		opts = append(opts, loads.WithRefMapping(mapping.Prefix, mapping.Dir))
+	}
+
+	return opts
+}
+
+// parse parses the flags of a command that takes a single spec argument.
+func parse(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		return "", errUsage
+	}
+
+	if fs.NArg() != 1 {
+		fmt.Fprintf(fs.Output(), "usage: loads %s [flags] <spec>\n", fs.Name())
+		fs.PrintDefaults()
+
+		return "", errUsage
+	}
+
+	return fs.Arg(0), nil
+}
@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+)
+
+func runLoad(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("load", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var lf loadFlags
+	lf.register(fs)
+
+	path, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	doc, err := lf.load(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "version:  %s\n", doc.Version())
+	fmt.Fprintf(stdout, "host:     %s\n", doc.Host())
+	fmt.Fprintf(stdout, "basePath: %s\n", doc.BasePath())
+
+	refs := make([]string, 0, len(doc.Analyzer.AllRefs()))
+	for _, ref := range doc.Analyzer.AllRefs() {
+		refs = append(refs, ref.String())
+	}
+	slices.Sort(refs)
+	refs = slices.Compact(refs)
+
+	fmt.Fprintf(stdout, "refs:     %d\n", len(refs))
+	for _, ref := range refs {
+		fmt.Fprintf(stdout, "  %s\n", ref)
+	}
+
+	return nil
+}
+
+func runDeps(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("deps", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var lf loadFlags
+	lf.register(fs)
+
+	path, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	doc, err := lf.load(path)
+	if err != nil {
+		return err
+	}
+
+	dependencies, err := doc.Dependencies()
+	if err != nil {
+		return err
+	}
+
+	for _, dependency := range dependencies {
+		fmt.Fprintln(stdout, dependency)
+	}
+
+	return nil
+}
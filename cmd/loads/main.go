@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Command loads is a command line front-end to the [github.com/go-openapi/loads] package.
+//
+// Usage:
+//
+//	loads <command> [flags] <spec>
+//
+// Commands:
+//
+//	load      load a spec and print its version, host, basePath and references
+//	expand    write a spec with all its references inlined
+//	bundle    write a spec with its references to other documents inlined
+//	convert   convert a spec between JSON and YAML
+//	deps      list the documents a spec depends on
+//	vendor    download a spec and all its references into a directory, with a lockfile
+//
+// Loading flags, shared by all commands:
+//
+//	-root dir         confine local reads to dir (repeatable): without it, local reads are
+//	                  not confined
+//	-network policy   network policy for remote references: "public" (the default: public
+//	                  addresses only), "offline" (no network access) or "any"
+//	-map prefix=dir   map a URL prefix to a local directory (repeatable)
+//
+// With -root and the default network policy, the tool is safe to run on untrusted specs: a
+// reference can neither read a local file outside the roots, nor reach a private network address.
+// Without -root, a spec or a reference may read any local file the tool can read.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+var (
+	errUsage        = errors.New("usage")
+	errInvalidFlags = errors.New("invalid flags")
+)
+
+// command is a subcommand of the loads tool.
+type command struct {
+	name  string
+	usage string
+	run   func(args []string, stdout, stderr io.Writer) error
+}
+
+func commands() []command {
+	return []command{
+		{name: "load", usage: "load a spec and print its version, host, basePath and references", run: runLoad},
+		{name: "expand", usage: "write a spec with all its references inlined", run: runExpand},
+		{name: "bundle", usage: "write a spec with its references to other documents inlined", run: runBundle},
+		{name: "convert", usage: "convert a spec between JSON and YAML", run: runConvert},
+		{name: "deps", usage: "list the documents a spec depends on", run: runDeps},
+		{name: "vendor", usage: "download a spec and all its references into a directory, with a lockfile", run: runVendor},
+	}
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, "loads:", err)
+		}
+
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	cmds := commands()
+	if len(args) == 0 {
+		printUsage(stderr, cmds)
+
+		return errUsage
+	}
+
+	idx := slices.IndexFunc(cmds, func(c command) bool { return c.name == args[0] })
+	if idx < 0 {
+		printUsage(stderr, cmds)
+
+		return errUsage
+	}
+
+	return cmds[idx].run(args[1:], stdout, stderr)
+}
+
+func printUsage(w io.Writer, cmds []command) {
+	var b strings.Builder
+	b.WriteString("usage: loads <command> [flags] <spec>\n\ncommands:\n")
+	for _, c := range cmds {
+		fmt.Fprintf(&b, "  %-10s %s\n", c.name, c.usage)
+	}
+
+	_, _ = io.WriteString(w, b.String())
+}
@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+const fixture = "../../testdata/yaml/swagger/spec.yml"
+
+func TestRun(t *testing.T) {
+	t.Run("should print usage without a command", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		require.ErrorIs(t, run(nil, &stdout, &stderr), errUsage)
+		assert.StringContainsT(t, stderr.String(), "vendor")
+
+		require.ErrorIs(t, run([]string{"unknown"}, &stdout, &stderr), errUsage)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("should print the summary of a spec", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		require.NoError(t, run([]string{"load", "-root", "../../testdata", fixture}, &stdout, &stderr))
+		assert.EqualT(t, `version:  2.0
+host:     api.example.com
+basePath: 
+refs:     3
+  #/definitions/a
+  #/definitions/b
+  ./test3-ter-model-schema.json#/definitions/b
+`, stdout.String())
+	})
+
+	t.Run("should confine local reads to the roots", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		require.Error(t, run([]string{"load", "-root", "../../testdata/json", fixture}, &stdout, &stderr))
+	})
+
+	t.Run("should resolve a relative path that looks like a URL against the working directory", func(t *testing.T) {
+		dir := t.TempDir()
+		work := filepath.Join(dir, "work")
+		spec, err := os.ReadFile("../../testdata/json/petstore.json")
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Join(work, "specs", "v1:"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(work, "specs", "v1:", "spec.json"), spec, 0o600))
+		t.Chdir(work)
+
+		var stdout, stderr bytes.Buffer
+		require.NoError(t, run([]string{"load", "-root", dir, "specs/v1://spec.json"}, &stdout, &stderr))
+		assert.StringContainsT(t, stdout.String(), "version:  2.0")
+	})
+
+	t.Run("should restrict the network", func(t *testing.T) {
+		pth := filepath.Join(t.TempDir(), "spec.json")
+		require.NoError(t, os.WriteFile(pth, []byte(`{"swagger": "2.0", "paths": {}, "definitions": {
+  "a": {"$ref": "http://127.0.0.1:1/model.json"}
+}}`), 0o600))
+
+		var stdout, stderr bytes.Buffer
+		require.ErrorIs(t, run([]string{"deps", pth}, &stdout, &stderr), loads.ErrForbiddenAddress)
+		require.ErrorIs(t, run([]string{"deps", "-root", filepath.Dir(pth), pth}, &stdout, &stderr), loads.ErrForbiddenAddress)
+		require.ErrorIs(t, run([]string{"deps", "-network", "offline", pth}, &stdout, &stderr), loads.ErrOffline)
+	})
+
+	t.Run("should reject invalid flags", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		require.ErrorIs(t, run([]string{"load", "-network", "some", fixture}, &stdout, &stderr), errUsage)
+		require.ErrorIs(t, run([]string{"load", "-network", "any", "-root", ".", fixture}, &stdout, &stderr), errInvalidFlags)
+		require.ErrorIs(t, run([]string{"load", fixture, fixture}, &stdout, &stderr), errUsage)
+	})
+}
+
+func TestDeps(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"deps", "-offline", fixture}, &stdout, &stderr))
+
+	abs, err := filepath.Abs("../../testdata/yaml/swagger/test3-ter-model-schema.json")
+	require.NoError(t, err)
+	assert.EqualT(t, filepath.ToSlash(abs)+"\n", stdout.String())
+}
+
+func TestExpand(t *testing.T) {
+	t.Run("should expand a spec", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		require.NoError(t, run([]string{"expand", fixture}, &stdout, &stderr))
+
+		document, err := loads.Analyzed(stdout.Bytes(), "")
+		require.NoError(t, err)
+		assert.Empty(t, document.Analyzer.AllRefs())
+	})
+
+	t.Run("should bundle a spec into a file", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "bundle.yaml")
+
+		var stdout, stderr bytes.Buffer
+		require.NoError(t, run([]string{"bundle", "-format", "yaml", "-o", out, fixture}, &stdout, &stderr))
+		assert.Empty(t, stdout.String())
+
+		document, err := loads.Spec(out)
+		require.NoError(t, err)
+
+		refs := make([]string, 0, len(document.Analyzer.AllRefs()))
+		for _, ref := range document.Analyzer.AllRefs() {
+			refs = append(refs, ref.String())
+		}
+		assert.ElementsMatch(t, []string{"#/definitions/a", "#/definitions/b"}, refs)
+	})
+
+	t.Run("should reject an invalid format", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		require.ErrorIs(t, run([]string{"expand", "-format", "xml", fixture}, &stdout, &stderr), errInvalidFlags)
+	})
+}
+
+func TestConvert(t *testing.T) {
+	dir := t.TempDir()
+	jsonFile := filepath.Join(dir, "spec.json")
+	yamlFile := filepath.Join(dir, "spec.yaml")
+
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"convert", "-o", jsonFile, fixture}, &stdout, &stderr))
+	require.NoError(t, run([]string{"convert", "-o", yamlFile, jsonFile}, &stdout, &stderr))
+
+	data, err := os.ReadFile(yamlFile)
+	require.NoError(t, err)
+	assert.StringContainsT(t, string(data), "        \"200\":\n          description: Success\n")
+
+	original, err := loads.Spec(fixture)
+	require.NoError(t, err)
+
+	converted, err := loads.Spec(yamlFile)
+	require.NoError(t, err)
+	assert.JSONEqT(t, string(original.Raw()), string(converted.Raw()))
+}
+
+func TestVendor(t *testing.T) {
+	t.Run("should vendor a spec with a lockfile", func(t *testing.T) {
+		dir := t.TempDir()
+
+		var stdout, stderr bytes.Buffer
+		require.NoError(t, run([]string{"vendor", "-o", dir, "-offline", fixture}, &stdout, &stderr))
+		assert.StringContainsT(t, stdout.String(), "spec.json")
+
+		lock, err := loads.ReadLockfile(filepath.Join(dir, loads.LockfileName))
+		require.NoError(t, err)
+		assert.Len(t, lock.Documents, 2)
+	})
+
+	t.Run("should map remote documents to a local mirror", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		require.NoError(t, run([]string{
+			"vendor", "-o", t.TempDir(), "-offline",
+			"-map", "https://example.com/specs/=../../testdata/yaml/swagger",
+			"https://example.com/specs/spec.yml",
+		}, &stdout, &stderr))
+		assert.StringContainsT(t, stdout.String(), "https://example.com/specs/test3-ter-model-schema.json")
+	})
+
+	t.Run("should reject invalid usage", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		require.ErrorIs(t, run([]string{"vendor"}, &stdout, &stderr), errUsage)
+		require.ErrorIs(t, run([]string{"vendor", "-map", "nodir", fixture}, &stdout, &stderr), errUsage)
+	})
+}
@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+)
+
+func runVendor(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("vendor", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", "vendor", "output directory")
+	var lf loadFlags
+	lf.register(fs)
+
+	path, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	doc, err := lf.load(path)
+	if err != nil {
+		return err
+	}
+
+	lock, err := doc.Vendor(*out)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range lock.Documents {
+		fmt.Fprintf(stdout, "%s  %s  %s\n", entry.SHA256, entry.Path, entry.URL)
+	}
+
+	return nil
+}
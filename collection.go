@@ -0,0 +1,288 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-openapi/swag/yamlutils"
+	yaml "go.yaml.in/yaml/v3"
+)
+
+// DocumentError reports a document of a collection that failed to load.
+type DocumentError struct {
+	// Location is the file or stream holding the document.
+	Location string
+
+	// Index is the position of the document in the collection.
+	Index int
+
+	Err error
+}
+
+func (e *DocumentError) Error() string {
+	return fmt.Sprintf("document %d in %q: %v", e.Index, e.Location, e.Err)
+}
+
+func (e *DocumentError) Unwrap() error {
+	return e.Err
+}
+
+// SpecCollection loads all the spec documents held by the stream at path, e.g. a YAML file with
+// several documents separated by "---", or a sequence of JSON objects.
+//
+// The returned collection holds one entry per document, in order. A document that fails to load
+// leaves a nil entry, and is reported by a [*DocumentError] with its index: the returned error
+// joins them all. A stream that cannot be read fails as a whole.
+//
+// All the documents share a single loader, configured by opts, and a single cache: a document
+// referenced by several of them is loaded only once. They are all located at path, which relative
+// "$ref"s are resolved against.
+//
+// The stream itself is read through the loader configured by opts, like any document: its loader
+// chain, loading options, ref mappings, scheme loaders and integrity verifier all apply. The built-in
+// loaders (see [DefaultLoaders], [SetRestrictedLoaders] and [RestrictedRootsLoaders]), including when
+// wrapped by [IntegrityVerifier.WrapMatches], yield the stream as it is fetched; any other loader yields
+// a single document.
+func SpecCollection(path string, opts ...LoaderOption) ([]*Document, error) {
+	c := newCollection(opts)
+
+	data, err := c.loader.fetch(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c.read(path, data)
+
+	return c.result()
+}
+
+// SpecCollectionFromReader loads all the spec documents read from r, as if they had been loaded
+// from baseURI. See [SpecCollection].
+func SpecCollectionFromReader(r io.Reader, baseURI string, opts ...LoaderOption) ([]*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errLoads(err)
+	}
+
+	c := newCollection(opts)
+	c.read(baseURI, data)
+
+	return c.result()
+}
+
+// SpecDir loads all the spec documents found in the local directory dir, i.e. the files with a
+// ".json", ".yaml" or ".yml" extension, in lexical order. Sub-directories are not visited.
+//
+// Each file may hold several documents, like with [SpecCollection]. A file that cannot be read
+// leaves a single nil entry in the collection, reported by a [*DocumentError].
+//
+// The directory is listed as is, but every file is read through the loader configured by opts, like
+// with [SpecCollection]: a file outside of the confinement of the loader fails to load.
+//
+// All the documents share a single loader and a single cache: a reference from one file to
+// another file of the directory that holds a single document does not load it again.
+func SpecDir(dir string, opts ...LoaderOption) ([]*Document, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errLoads(err)
+	}
+
+	c := newCollection(opts)
+	for _, entry := range entries {
+		if entry.IsDir() || !isSpecFile(entry.Name()) {
+			continue
+		}
+
+		location := filepath.Join(dir, entry.Name())
+		data, err := c.loader.fetch(location)
+		if err != nil {
+			c.fail(location, err)
+
+			continue
+		}
+
+		c.read(location, data)
+	}
+
+	return c.result()
+}
+
+func isSpecFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
+
+// collection accumulates the documents of a multi-document load.
+type collection struct {
+	loader    *loader
+	documents []*Document
+	errs      []error
+}
+
+func newCollection(opts []LoaderOption) *collection {
+	ldr := loaderFromOptions(opts)
+	ldr.cache = newDocCache()
+
+	return &collection{loader: ldr}
+}
+
+// read adds all the documents held by data, found at location.
+func (c *collection) read(location string, data []byte) {
+	first := len(c.documents)
+
+	splitDocuments(data, func(raw json.RawMessage, err error) {
+		if err != nil {
+			c.fail(location, err)
+
+			return
+		}
+
+		document, err := Analyzed(raw, "")
+		if err != nil {
+			c.fail(location, err)
+
+			return
+		}
+
+		document.specFilePath = location
+		document.pathLoader = c.loader
+		c.documents = append(c.documents, document)
+	})
+
+	if len(c.documents) == first+1 && c.documents[first] != nil && location != "" {
+		// a reference to a file with a single document resolves to this document
+		c.loader.cache.put(location, c.documents[first].Raw())
+	}
+}
+
+func (c *collection) fail(location string, err error) {
+	c.errs = append(c.errs, &DocumentError{Location: location, Index: len(c.documents), Err: errLoads(err)})
+	c.documents = append(c.documents, nil)
+}
+
+func (c *collection) result() ([]*Document, error) {
+	return c.documents, errors.Join(c.errs...)
+}
+
+// splitDocuments calls fn with the JSON of every document in data, in order.
+//
+// data is either a sequence of JSON values, or a YAML stream. A document that is not an object is
+// reported to fn as an error. A syntax error stops the iteration, since the remainder of the stream
+// cannot be told apart.
+func splitDocuments(data []byte, fn func(json.RawMessage, error)) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return
+	}
+
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		splitJSON(trimmed, fn)
+
+		return
+	}
+
+	splitYAML(trimmed, fn)
+}
+
+func splitJSON(data []byte, fn func(json.RawMessage, error)) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var raw json.RawMessage
+		err := dec.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			fn(nil, err)
+
+			return
+		}
+
+		if raw[0] != '{' {
+			fn(nil, fmt.Errorf("%w: a spec document must be an object", ErrLoads))
+
+			continue
+		}
+
+		fn(raw, nil)
+	}
+}
+
+func splitYAML(data []byte, fn func(json.RawMessage, error)) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var node yaml.Node
+		err := dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			fn(nil, err)
+
+			return
+		}
+
+		if len(node.Content) == 0 || node.Content[0].ShortTag() == "!!null" {
+			continue // an empty document, e.g. after a trailing "---"
+		}
+
+		if node.Content[0].Kind != yaml.MappingNode {
+			fn(nil, fmt.Errorf("%w: a spec document must be an object", ErrLoads))
+
+			continue
+		}
+
+		fn(yamlutils.YAMLToJSON(&node))
+	}
+}
+
+// docCache holds the documents loaded by a loader, by lock key.
+//
+// It is shared by all the documents of a collection, which may be used concurrently.
+type docCache struct {
+	mu   sync.RWMutex
+	docs map[string]json.RawMessage
+}
+
+func newDocCache() *docCache {
+	return &docCache{docs: make(map[string]json.RawMessage)}
+}
+
+func (c *docCache) get(path string) (json.RawMessage, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	b, ok := c.docs[lockKey(path)]
+
+	return b, ok
+}
+
+func (c *docCache) put(path string, data json.RawMessage) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.docs[lockKey(path)] = slices.Clip(data)
+}
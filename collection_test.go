@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-openapi/swag/loading"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+// collectionFixture is a directory of specs, with a multi-document YAML stream.
+const collectionFixture = "testdata/yaml/collection"
+
+// countingLoaders installs the built-in JSON/YAML chain, counting the fetches of the document named name.
+func countingLoaders(name string, calls *atomic.Int32) LoaderOption {
+	fetch := func(pth string, opts ...loading.Option) (json.RawMessage, error) {
+		if filepath.Base(pth) == name {
+			calls.Add(1)
+		}
+
+		return JSONDoc(pth, opts...)
+	}
+
+	return WithDocLoaderMatches(
+		newFetchingDocLoader(fetch, yamlToJSON, loading.YAMLMatcher),
+		newFetchingDocLoader(fetch, asJSON, nil),
+	)
+}
+
+func TestSpecCollection(t *testing.T) {
+	t.Run("should load every document of a YAML stream", func(t *testing.T) {
+		pth := filepath.Join(collectionFixture, "stream.yaml")
+
+		var calls atomic.Int32
+		documents, err := SpecCollection(pth, countingLoaders("common.yaml", &calls))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoads)
+		require.Len(t, documents, 3)
+
+		var docErr *DocumentError
+		require.ErrorAs(t, err, &docErr)
+		assert.EqualT(t, 1, docErr.Index)
+		assert.EqualT(t, pth, docErr.Location)
+		assert.Nil(t, documents[1])
+
+		titles := make([]string, 0, 2)
+		for _, document := range []*Document{documents[0], documents[2]} {
+			require.NotNil(t, document)
+			assert.EqualT(t, pth, document.SpecFilePath())
+			titles = append(titles, document.Spec().Info.Title)
+
+			expanded, err := document.Expanded()
+			require.NoError(t, err)
+
+			for _, op := range expanded.Analyzer.Operations()["GET"] {
+				assert.TrueT(t, op.Responses.Default.Schema.Properties["message"].Type.Contains("string"))
+			}
+		}
+		assert.Equal(t, []string{"pets", "stores"}, titles)
+
+		assert.EqualT(t, int32(1), calls.Load(), "the shared document should be loaded once for the whole collection")
+		assert.True(t, documents[0].docLoader() == documents[2].docLoader(), "the documents should share a loader")
+	})
+
+	t.Run("should load a sequence of JSON documents", func(t *testing.T) {
+		stream := `{"swagger":"2.0","info":{"title":"a","version":"1"},"paths":{}}
+{"swagger":"2.0","info":{"title":"b","version":"1"},"paths":{}}
+"not a spec"`
+
+		documents, err := SpecCollectionFromReader(strings.NewReader(stream), "specs.json")
+		require.Len(t, documents, 3)
+		assert.EqualT(t, "a", documents[0].Spec().Info.Title)
+		assert.EqualT(t, "b", documents[1].Spec().Info.Title)
+		assert.EqualT(t, "specs.json", documents[1].SpecFilePath())
+
+		var docErr *DocumentError
+		require.ErrorAs(t, err, &docErr)
+		assert.EqualT(t, 2, docErr.Index)
+	})
+
+	t.Run("should stop at a syntax error", func(t *testing.T) {
+		stream := "swagger: \"2.0\"\ninfo:\n  title: a\n---\nswagger: [\n---\nswagger: \"2.0\"\n"
+
+		documents, err := SpecCollectionFromReader(strings.NewReader(stream), "")
+		require.Error(t, err)
+		require.Len(t, documents, 2)
+		assert.NotNil(t, documents[0])
+		assert.Nil(t, documents[1])
+	})
+
+	t.Run("should yield an empty collection for an empty stream", func(t *testing.T) {
+		documents, err := SpecCollectionFromReader(strings.NewReader("\n---\n"), "")
+		require.NoError(t, err)
+		assert.Empty(t, documents)
+	})
+
+	t.Run("should fail when the stream cannot be read", func(t *testing.T) {
+		_, err := SpecCollection(filepath.Join(t.TempDir(), "missing.yaml"))
+		require.ErrorIs(t, err, ErrLoads)
+	})
+
+	t.Run("should read the stream through the loader", func(t *testing.T) {
+		documents, err := SpecCollection("https://example.com/specs/stream.yaml",
+			WithRefMapping("https://example.com/specs/", collectionFixture), WithOffline())
+		require.Len(t, documents, 3)
+		var docErr *DocumentError
+		require.ErrorAs(t, err, &docErr)
+		assert.EqualT(t, 1, docErr.Index)
+
+		_, err = SpecCollection(filepath.Join(collectionFixture, "stream.yaml"),
+			WithDocLoaderMatches(RestrictedRootsLoaders([]string{t.TempDir()}, nil)...))
+		require.ErrorIs(t, err, ErrLoads)
+	})
+}
+
+func TestSpecDir(t *testing.T) {
+	dir := collectionFixture
+
+	var calls atomic.Int32
+	documents, err := SpecDir(dir, countingLoaders("common.yaml", &calls))
+	require.Len(t, documents, 5)
+
+	var docErr *DocumentError
+	require.ErrorAs(t, err, &docErr)
+	assert.EqualT(t, 3, docErr.Index)
+	assert.EqualT(t, filepath.Join(dir, "stream.yaml"), docErr.Location)
+
+	assert.EqualT(t, filepath.Join(dir, "common.yaml"), documents[0].SpecFilePath())
+	assert.EqualT(t, filepath.Join(dir, "pets.yml"), documents[1].SpecFilePath())
+	assert.EqualT(t, filepath.Join(dir, "stream.yaml"), documents[2].SpecFilePath())
+	assert.EqualT(t, filepath.Join(dir, "stream.yaml"), documents[4].SpecFilePath())
+
+	for _, i := range []int{1, 2, 4} {
+		_, err := documents[i].Expanded()
+		require.NoError(t, err)
+	}
+	assert.EqualT(t, int32(1), calls.Load(), "a file of the directory should not be loaded again")
+
+	t.Run("should report an unreadable file", func(t *testing.T) {
+		_, err := SpecDir(dir, WithLoadingOptions(loading.WithFS(os.DirFS(t.TempDir()))))
+		require.Error(t, err)
+
+		var errs interface{ Unwrap() []error }
+		require.True(t, errors.As(err, &errs))
+		assert.Len(t, errs.Unwrap(), 3)
+	})
+
+	t.Run("should read every file through the loader", func(t *testing.T) {
+		documents, err := SpecDir(dir, WithDocLoaderMatches(RestrictedRootsLoaders([]string{t.TempDir()}, nil)...))
+		require.Len(t, documents, 3)
+
+		var errs interface{ Unwrap() []error }
+		require.True(t, errors.As(err, &errs))
+		assert.Len(t, errs.Unwrap(), 3)
+	})
+
+	t.Run("should fail on a missing directory", func(t *testing.T) {
+		_, err := SpecDir(filepath.Join(dir, "missing"))
+		require.ErrorIs(t, err, ErrLoads)
+	})
+}
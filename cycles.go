@@ -0,0 +1,364 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RefLocation locates a "$ref" in a document.
+type RefLocation struct {
+	// Document is the location of the document holding the "$ref".
+	Document string
+
+	// Pointer is the JSON pointer of the object holding the "$ref" in Document.
+	Pointer string
+
+	// Ref is the reference, as found in the document.
+	Ref string
+}
+
+func (l RefLocation) String() string {
+	return l.Document + "#" + l.Pointer
+}
+
+// RefCycle is a reference cycle: each "$ref" designates a node that contains the next one, and the
+// last one designates a node that contains the first one.
+type RefCycle []RefLocation
+
+func (c RefCycle) String() string {
+	var b strings.Builder
+	for _, step := range c {
+		b.WriteString(step.String())
+		b.WriteString(" -> ")
+	}
+
+	if len(c) > 0 {
+		b.WriteString(c[0].String())
+	}
+
+	return b.String()
+}
+
+// CycleError reports a reference cycle found during an expansion configured with [ExpandCyclesFail].
+//
+// It matches [ErrCircularRef] and [ErrLoads] with [errors.Is].
+type CycleError struct {
+	Cycle RefCycle
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrLoads, ErrCircularRef, e.Cycle)
+}
+
+// Unwrap allows [errors.Is] to match [ErrCircularRef] and [ErrLoads].
+func (e *CycleError) Unwrap() []error {
+	return []error{ErrLoads, ErrCircularRef}
+}
+
+// Cycles reports the reference cycles of the document, including cycles that span several documents.
+//
+// Each elementary cycle is reported once, as the ordered list of the "$ref"s that form it, starting
+// with the least of them. Cycles are reported in a deterministic order. A document without any cycle
+// yields an empty result.
+//
+// Referenced documents are loaded through the document's loader, like with [Document.Expanded].
+func (d *Document) Cycles() ([]RefCycle, error) {
+	root, err := decodeJSON(d.Raw())
+	if err != nil {
+		return nil, errLoads(err)
+	}
+
+	g := &refGraph{
+		documents: refDocuments{
+			loader: d.docLoader(),
+			docs:   map[string]any{lockKey(d.specFilePath): root},
+		},
+		index: make(map[string]int),
+	}
+
+	if err := g.build(root, d.specFilePath); err != nil {
+		return nil, err
+	}
+
+	return g.cycles(), nil
+}
+
+// refFrame is a reference being followed: the identity of its target, and where the "$ref" was found.
+type refFrame struct {
+	target string
+	from   RefLocation
+}
+
+// cycleOf returns the cycle closed by a "$ref" at from to target, if target is already being followed.
+func cycleOf(stack []refFrame, target string, from RefLocation) (RefCycle, bool) {
+	idx := slices.IndexFunc(stack, func(f refFrame) bool { return f.target == target })
+	if idx < 0 {
+		return nil, false
+	}
+
+	cycle := make(RefCycle, 0, len(stack)-idx)
+	for _, frame := range stack[idx+1:] {
+		cycle = append(cycle, frame.from)
+	}
+
+	return append(cycle, from), true
+}
+
+// refGraph is the graph of the "$ref"s of a document. Each node is the target of a "$ref", or the root
+// document, with an edge for every "$ref" found within it.
+type refGraph struct {
+	documents refDocuments
+	nodes     []refNode
+	index     map[string]int // node id -> index in nodes
+}
+
+// refNode is a node of a [refGraph]: the node at JSON pointer fragment in the document at base.
+// The fragment is percent-decoded.
+type refNode struct {
+	base     string
+	fragment string
+	from     RefLocation // the first "$ref" found to the node
+	edges    []refEdge
+}
+
+// refEdge is a "$ref" found at from, within a node, to the node at index to.
+type refEdge struct {
+	to   int
+	from RefLocation
+}
+
+// build adds the root document at base to the graph, then every node reachable from it.
+func (g *refGraph) build(root any, base string) error {
+	g.add(lockKey(base)+"#", refNode{base: base})
+	if err := g.walk(root, base, "", 0); err != nil {
+		return err
+	}
+
+	for i := 1; i < len(g.nodes); i++ {
+		node := g.nodes[i]
+		resolved, err := g.documents.resolve(node.base, node.fragment)
+		if err != nil {
+			return errLoads(fmt.Errorf("resolving %q at %q: %w", node.from.Ref, node.from, err))
+		}
+
+		if err := g.walk(resolved, node.base, node.fragment, i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// add adds a node under id, unless it is already known, and returns its index.
+func (g *refGraph) add(id string, node refNode) int {
+	if i, ok := g.index[id]; ok {
+		return i
+	}
+
+	g.index[id] = len(g.nodes)
+	g.nodes = append(g.nodes, node)
+
+	return len(g.nodes) - 1
+}
+
+// walk adds an edge from the node at index src for every "$ref" found in node, at JSON pointer
+// pointer in the document at base, in a depth-first order.
+func (g *refGraph) walk(node any, base, pointer string, src int) error {
+	switch n := node.(type) {
+	case map[string]any:
+		if ref, ok := n[refKey].(string); ok {
+			from := RefLocation{Document: base, Pointer: pointer, Ref: ref}
+			document, fragment := splitRef(ref)
+			fragment = decodeFragment(fragment)
+			targetDoc := resolveDocument(base, document)
+			to := g.add(lockKey(targetDoc)+"#"+fragment, refNode{base: targetDoc, fragment: fragment, from: from})
+			g.nodes[src].edges = append(g.nodes[src].edges, refEdge{to: to, from: from})
+
+			return nil
+		}
+
+		keys := make([]string, 0, len(n))
+		for k := range n {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			if err := g.walk(n[k], base, pointer+"/"+escapePointerToken(k), src); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, e := range n {
+			if err := g.walk(e, base, pointer+"/"+strconv.Itoa(i), src); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// cycles enumerates the elementary cycles of the graph, with Johnson's algorithm.
+//
+// Each cycle is rotated to start at its least "$ref", and cycles are sorted.
+func (g *refGraph) cycles() []RefCycle {
+	j := johnson{
+		graph:   g,
+		blocked: make([]bool, len(g.nodes)),
+		blocks:  make([]map[int]bool, len(g.nodes)),
+	}
+
+	for j.start = range g.nodes {
+		for v := j.start; v < len(g.nodes); v++ {
+			j.blocked[v] = false
+			j.blocks[v] = make(map[int]bool)
+		}
+
+		j.circuit(j.start)
+	}
+
+	slices.SortFunc(j.found, compareCycles)
+
+	return j.found
+}
+
+// johnson holds the state of Johnson's algorithm, enumerating the cycles whose least node is start.
+type johnson struct {
+	graph   *refGraph
+	start   int
+	stack   []int
+	blocked []bool
+	blocks  []map[int]bool
+	found   []RefCycle
+}
+
+func (j *johnson) circuit(v int) bool {
+	closed := false
+	j.stack = append(j.stack, v)
+	j.blocked[v] = true
+
+	for _, w := range j.successors(v) {
+		switch {
+		case w == j.start:
+			j.emit()
+			closed = true
+		case !j.blocked[w]:
+			if j.circuit(w) {
+				closed = true
+			}
+		}
+	}
+
+	if closed {
+		j.unblock(v)
+	} else {
+		for _, w := range j.successors(v) {
+			j.blocks[w][v] = true
+		}
+	}
+
+	j.stack = j.stack[:len(j.stack)-1]
+
+	return closed
+}
+
+func (j *johnson) unblock(u int) {
+	j.blocked[u] = false
+	for w := range j.blocks[u] {
+		delete(j.blocks[u], w)
+		if j.blocked[w] {
+			j.unblock(w)
+		}
+	}
+}
+
+// successors returns the nodes designated from v, not below start, once each.
+func (j *johnson) successors(v int) []int {
+	var out []int
+	for _, e := range j.graph.nodes[v].edges {
+		if e.to >= j.start && !slices.Contains(out, e.to) {
+			out = append(out, e.to)
+		}
+	}
+
+	return out
+}
+
+// emit records the cycle of the nodes on the stack, once for every combination of the "$ref"s
+// that link them.
+func (j *johnson) emit() {
+	cycles := []RefCycle{nil}
+	for i, v := range j.stack {
+		next := j.start
+		if i+1 < len(j.stack) {
+			next = j.stack[i+1]
+		}
+
+		var extended []RefCycle
+		for _, e := range j.graph.nodes[v].edges {
+			if e.to != next {
+				continue
+			}
+
+			for _, cycle := range cycles {
+				extended = append(extended, append(slices.Clip(cycle), e.from))
+			}
+		}
+		cycles = extended
+	}
+
+	for _, cycle := range cycles {
+		least := 0
+		for i := range cycle {
+			if compareRefLocations(cycle[i], cycle[least]) < 0 {
+				least = i
+			}
+		}
+
+		j.found = append(j.found, append(slices.Clone(cycle[least:]), cycle[:least]...))
+	}
+}
+
+func compareRefLocations(a, b RefLocation) int {
+	if c := strings.Compare(a.String(), b.String()); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a.Ref, b.Ref)
+}
+
+func compareCycles(a, b RefCycle) int {
+	return slices.CompareFunc(a, b, compareRefLocations)
+}
+
+// refDocuments loads and caches the documents designated by "$ref"s.
+type refDocuments struct {
+	loader *loader
+	docs   map[string]any // lock key -> decoded document
+}
+
+// resolve returns the node at fragment in the document at location.
+func (r refDocuments) resolve(location, fragment string) (any, error) {
+	key := lockKey(location)
+	doc, ok := r.docs[key]
+	if !ok {
+		b, err := r.loader.Load(location)
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err = decodeJSON(b)
+		if err != nil {
+			return nil, fmt.Errorf("decoding %q: %w", location, err)
+		}
+		r.docs[key] = doc
+	}
+
+	return resolvePointer(doc, fragment)
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+// cyclicFixture is a spec with a local cycle and a cycle across documents.
+const cyclicFixture = "testdata/json/cycles/spec.json"
+
+func TestCycles(t *testing.T) {
+	t.Run("should report no cycle in an acyclic spec", func(t *testing.T) {
+		document, err := Spec("testdata/yaml/swagger/spec.yml")
+		require.NoError(t, err)
+
+		cycles, err := document.Cycles()
+		require.NoError(t, err)
+		assert.Empty(t, cycles)
+	})
+
+	t.Run("should report local and cross-document cycles", func(t *testing.T) {
+		pth := cyclicFixture
+		document, err := Spec(pth)
+		require.NoError(t, err)
+
+		cycles, err := document.Cycles()
+		require.NoError(t, err)
+		require.Len(t, cycles, 2)
+
+		other := filepath.ToSlash(filepath.Join(filepath.Dir(pth), "other.json"))
+		assert.Equal(t, RefCycle{
+			{Document: other, Pointer: "/definitions/B/properties/a", Ref: "spec.json#/definitions/A"},
+			{Document: pth, Pointer: "/definitions/A/properties/b", Ref: "other.json#/definitions/B"},
+		}, cycles[0])
+		assert.Equal(t, RefCycle{
+			{Document: pth, Pointer: "/definitions/Node/properties/next", Ref: "#/definitions/Node"},
+		}, cycles[1])
+		assert.EqualT(t, pth+"#/definitions/Node/properties/next -> "+pth+"#/definitions/Node/properties/next", cycles[1].String())
+	})
+
+	t.Run("should report every cycle through a shared node", func(t *testing.T) {
+		// X -> Y -> Z -> X, and X -> Z -> X
+		pth := "testdata/json/cycles/shared-node.json"
+		document, err := Spec(pth)
+		require.NoError(t, err)
+
+		cycles, err := document.Cycles()
+		require.NoError(t, err)
+		require.Len(t, cycles, 2)
+
+		assert.Equal(t, RefCycle{
+			{Document: pth, Pointer: "/definitions/X/properties/y", Ref: "#/definitions/Y"},
+			{Document: pth, Pointer: "/definitions/Y/properties/z", Ref: "#/definitions/Z"},
+			{Document: pth, Pointer: "/definitions/Z/properties/x", Ref: "#/definitions/X"},
+		}, cycles[0])
+		assert.Equal(t, RefCycle{
+			{Document: pth, Pointer: "/definitions/X/properties/z", Ref: "#/definitions/Z"},
+			{Document: pth, Pointer: "/definitions/Z/properties/x", Ref: "#/definitions/X"},
+		}, cycles[1])
+	})
+
+	t.Run("should percent-decode references before reading their JSON pointer", func(t *testing.T) {
+		// "%7E1" decodes to "~1", which then designates a "/" in the key
+		pth := "testdata/json/cycles/encoded-pointer.json"
+		document, err := Spec(pth)
+		require.NoError(t, err)
+
+		cycles, err := document.Cycles()
+		require.NoError(t, err)
+		require.Len(t, cycles, 1)
+		assert.Equal(t, RefCycle{
+			{Document: pth, Pointer: "/definitions/a~1b/properties/next", Ref: "#/definitions/a%7E1b"},
+		}, cycles[0])
+	})
+}
+
+func TestExpandedPartialCycles(t *testing.T) {
+	document, err := Spec(cyclicFixture)
+	require.NoError(t, err)
+
+	t.Run("should keep cycles as references by default", func(t *testing.T) {
+		expanded, err := document.ExpandedPartial()
+		require.NoError(t, err)
+
+		node := expanded.Spec().Definitions["Node"]
+		next := node.Properties["next"]
+		assert.EqualT(t, "", next.Ref.String())
+		nextNext := next.Properties["next"]
+		assert.EqualT(t, "#/definitions/Node", nextNext.Ref.String())
+
+		// the cross-document cycle is kept as a reference valid from the root document
+		a := expanded.Spec().Definitions["A"]
+		b := a.Properties["b"]
+		backToA := b.Properties["a"]
+		assert.EqualT(t, "", backToA.Ref.String())
+		backToB := backToA.Properties["b"]
+		assert.EqualT(t, "./other.json#/definitions/B", backToB.Ref.String())
+
+		// the preserved references still resolve, and still form cycles
+		cycles, err := expanded.Cycles()
+		require.NoError(t, err)
+		assert.Len(t, cycles, 2)
+	})
+
+	t.Run("should unroll cycles up to a depth", func(t *testing.T) {
+		expanded, err := document.ExpandedPartial(ExpandCyclesUnroll(2))
+		require.NoError(t, err)
+
+		node := expanded.Spec().Definitions["Node"]
+		next := node.Properties["next"]
+		nextNext := next.Properties["next"]
+		assert.EqualT(t, "", nextNext.Ref.String())
+		last := nextNext.Properties["next"]
+		assert.EqualT(t, "#/definitions/Node", last.Ref.String())
+	})
+
+	t.Run("should fail on cycles", func(t *testing.T) {
+		_, err := document.ExpandedPartial(ExpandCyclesFail())
+		require.ErrorIs(t, err, ErrCircularRef)
+		require.ErrorIs(t, err, ErrLoads)
+
+		var cycleErr *CycleError
+		require.ErrorAs(t, err, &cycleErr)
+		assert.NotEmpty(t, cycleErr.Cycle)
+	})
+
+	t.Run("should not fail on cycles that are not inlined", func(t *testing.T) {
+		_, err := document.ExpandedPartial(ExpandCyclesFail(), ExpandTargetPrefix("/responses"))
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Dependencies returns the locations of the documents the spec depends on, directly or
+// transitively, breadth first and in a deterministic order.
+//
+// Every dependency is loaded through the document's loader (so [WithRefMapping], [WithOffline] and
+// confinement options apply), to follow its own references. Local dependencies are designated by
+// their absolute path, remote ones by their URL.
+func (d *Document) Dependencies() ([]string, error) {
+	var locations []string
+	err := d.walkDependencies(func(location string, _ fetchedDocument) error {
+		locations = append(locations, lockKey(location))
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return locations, nil
+}
+
+// walkDependencies loads the documents the spec depends on, breadth first, and calls fn with the
+// location and the content of each of them, once. Each document is fetched once, and yields both
+// its raw bytes and its JSON.
+func (d *Document) walkDependencies(fn func(location string, doc fetchedDocument) error) error {
+	ldr := d.docLoader()
+	seen := map[string]struct{}{lockKey(d.specFilePath): {}}
+
+	type pending struct {
+		location string
+		raw      json.RawMessage
+	}
+	queue := []pending{{location: d.specFilePath, raw: d.Raw()}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		node, err := decodeJSON(current.raw)
+		if err != nil {
+			return errLoads(fmt.Errorf("walking the references of %q: %w", current.location, err))
+		}
+
+		var locations []string
+		walkRefs(node, func(_ map[string]any, ref string) {
+			if document, _ := splitRef(ref); document != "" {
+				locations = append(locations, resolveDocument(current.location, document))
+			}
+		})
+
+		for _, location := range locations {
+			key := lockKey(location)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			doc, err := ldr.fetchDocument(location)
+			if err != nil {
+				return err
+			}
+
+			if err := fn(location, doc); err != nil {
+				return err
+			}
+
+			queue = append(queue, pending{location: location, raw: doc.json})
+		}
+	}
+
+	return nil
+}
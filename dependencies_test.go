@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestDependencies(t *testing.T) {
+	t.Run("should list transitive dependencies once", func(t *testing.T) {
+		dir := t.TempDir()
+		for name, content := range map[string]string{
+			"spec.json": `{"swagger": "2.0", "paths": {}, "definitions": {
+  "a": {"$ref": "models/pet.json#/definitions/Pet"},
+  "b": {"$ref": "common.yaml#/definitions/Error"}
+}}`,
+			"models/pet.json": `{"definitions": {"Pet": {"properties": {"error": {"$ref": "../common.yaml#/definitions/Error"}}}}}`,
+			"common.yaml":     "definitions:\n  Error:\n    type: string\n",
+		} {
+			pth := filepath.Join(dir, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0o750))
+			require.NoError(t, os.WriteFile(pth, []byte(content), 0o600))
+		}
+
+		document, err := Spec(filepath.Join(dir, "spec.json"))
+		require.NoError(t, err)
+
+		dependencies, err := document.Dependencies()
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			filepath.ToSlash(filepath.Join(dir, "models", "pet.json")),
+			filepath.ToSlash(filepath.Join(dir, "common.yaml")),
+		}, dependencies)
+	})
+
+	t.Run("should fail on a missing dependency", func(t *testing.T) {
+		document, err := Analyzed([]byte(`{"swagger": "2.0", "definitions": {"a": {"$ref": "https://example.com/missing.json"}}}`), "", WithOffline())
+		require.NoError(t, err)
+
+		_, err = document.Dependencies()
+		require.ErrorIs(t, err, ErrOffline)
+	})
+}
@@ -0,0 +1,326 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const defaultMaxDifferences = 10
+
+// Difference is a location where two documents differ, reported by [Equivalent].
+type Difference struct {
+	// Pointer is the JSON pointer of the location, in the normalized documents.
+	Pointer string
+
+	// A and B are the JSON values at the location in each document, nil when absent.
+	A, B json.RawMessage
+}
+
+func (d Difference) String() string {
+	switch {
+	case d.A == nil:
+		return fmt.Sprintf("%s: only in b: %s", d.Pointer, d.B)
+	case d.B == nil:
+		return fmt.Sprintf("%s: only in a: %s", d.Pointer, d.A)
+	default:
+		return fmt.Sprintf("%s: %s != %s", d.Pointer, d.A, d.B)
+	}
+}
+
+// CompareOption configures [Equivalent].
+type CompareOption func(*compareOptions)
+
+type compareOptions struct {
+	ignoreDocs     bool
+	maxDifferences int
+}
+
+// CompareIgnoreDocs ignores the documentation of the API, which does not affect the wire contract:
+// the info, tags and external docs of the spec, and the descriptions, summaries, titles and examples
+// found anywhere in it.
+func CompareIgnoreDocs() CompareOption {
+	return func(o *compareOptions) {
+		o.ignoreDocs = true
+	}
+}
+
+// CompareMaxDifferences sets the number of differences reported by [Equivalent]. The default is 10.
+//
+// A negative value reports all the differences.
+func CompareMaxDifferences(n int) CompareOption {
+	return func(o *compareOptions) {
+		o.maxDifferences = n
+	}
+}
+
+// Equivalent tells if two documents describe the same API, and reports the first differences.
+//
+// The documents are compared by their resolved semantics, rather than by their text:
+//
+//   - both documents are expanded, so how the spec is split into files and references does not matter;
+//   - the shared definitions, parameters and responses are not compared by name, only as inlined
+//     where they are used;
+//   - the global consumes, produces, schemes and security requirements, as well as the parameters of a
+//     path item, are compared as applied to every operation;
+//   - the order of keys, and of arrays that are sets (e.g. "required", "enum" or the parameters of an
+//     operation), does not matter.
+//
+// Circular references cannot be inlined: they are compared as references.
+//
+// The differences are located by JSON pointers into the normalized documents.
+func Equivalent(a, b *Document, opts ...CompareOption) (bool, []Difference, error) {
+	o := compareOptions{maxDifferences: defaultMaxDifferences}
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	left, err := normalizedDocument(a, o)
+	if err != nil {
+		return false, nil, err
+	}
+
+	right, err := normalizedDocument(b, o)
+	if err != nil {
+		return false, nil, err
+	}
+
+	c := &comparer{max: o.maxDifferences}
+	c.compare("", left, right)
+
+	return len(c.differences) == 0, c.differences, nil
+}
+
+// componentSections are the sections of named components, compared only where they are used.
+var componentSections = []string{"definitions", "parameters", "responses"}
+
+// docKeys are the keywords of documentation, ignored by [CompareIgnoreDocs].
+var docKeys = []string{"description", "summary", "title", "example", "examples", "externalDocs"}
+
+// namedKeys hold maps whose keys are names, rather than keywords.
+var namedKeys = []string{"properties", "patternProperties", "definitions", "parameters", "responses", "headers", "securityDefinitions", "scopes", "paths"}
+
+// setKeys hold arrays whose order does not matter.
+var setKeys = []string{"required", "enum", "schemes", "consumes", "produces", "tags", "parameters", "security"}
+
+func normalizedDocument(d *Document, o compareOptions) (any, error) {
+	expanded, err := d.Expanded()
+	if err != nil {
+		return nil, err
+	}
+
+	var root map[string]any
+	if err := json.Unmarshal(expanded.Raw(), &root); err != nil {
+		return nil, errLoads(err)
+	}
+
+	for _, section := range componentSections {
+		delete(root, section)
+	}
+
+	paths, _ := root["paths"].(map[string]any)
+	for _, p := range slices.Sorted(maps.Keys(paths)) {
+		item, ok := paths[p].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		shared, _ := item["parameters"].([]any)
+		for _, method := range operationMethods {
+			operation, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			for _, key := range inheritedKeys {
+				if _, defined := operation[key]; !defined && root[key] != nil {
+					operation[key] = deepCopy(root[key])
+				}
+			}
+
+			operation["parameters"] = mergeParameters(shared, operation["parameters"])
+		}
+		delete(item, "parameters")
+	}
+
+	for _, key := range inheritedKeys {
+		delete(root, key)
+	}
+
+	if o.ignoreDocs {
+		delete(root, "info")
+		delete(root, "tags")
+	}
+
+	return normalize(root, false, o.ignoreDocs), nil
+}
+
+// mergeParameters applies the parameters of a path item to one of its operations, which may override them.
+func mergeParameters(shared []any, own any) any {
+	parameters, _ := own.([]any)
+	if len(shared) == 0 {
+		return own
+	}
+
+	merged := slices.Clone(parameters)
+	for _, p := range shared {
+		overridden := slices.ContainsFunc(parameters, func(o any) bool {
+			return parameterKey(o) == parameterKey(p)
+		})
+		if !overridden {
+			merged = append(merged, deepCopy(p))
+		}
+	}
+
+	return merged
+}
+
+func parameterKey(p any) string {
+	parameter, _ := p.(map[string]any)
+
+	return fmt.Sprint(parameter["in"], "/", parameter["name"])
+}
+
+// normalize strips the documentation if required, and sorts the arrays that are sets.
+//
+// named tells that the keys of node are names, not keywords.
+func normalize(node any, named, ignoreDocs bool) any {
+	switch n := node.(type) {
+	case map[string]any:
+		for k, v := range n {
+			if !named && ignoreDocs && slices.Contains(docKeys, k) {
+				delete(n, k)
+
+				continue
+			}
+
+			child := normalize(v, !named && slices.Contains(namedKeys, k), ignoreDocs)
+			if list, ok := child.([]any); ok && !named && slices.Contains(setKeys, k) {
+				sortSet(list)
+			}
+			n[k] = child
+		}
+
+		return n
+	case []any:
+		for i, v := range n {
+			n[i] = normalize(v, false, ignoreDocs)
+		}
+
+		return n
+	default:
+		return n
+	}
+}
+
+// sortSet sorts the elements of a set: parameters by location and name, other values by their JSON.
+func sortSet(list []any) {
+	key := func(v any) string {
+		if parameter, ok := v.(map[string]any); ok && parameter["in"] != nil {
+			return parameterKey(parameter)
+		}
+
+		b, _ := json.Marshal(v) //nolint:errchkjson // a generic JSON tree always marshals
+
+		return string(b)
+	}
+
+	slices.SortStableFunc(list, func(a, b any) int {
+		return strings.Compare(key(a), key(b))
+	})
+}
+
+type comparer struct {
+	max         int
+	differences []Difference
+}
+
+func (c *comparer) full() bool {
+	return c.max >= 0 && len(c.differences) >= c.max
+}
+
+func (c *comparer) compare(pointer string, a, b any) {
+	if c.full() {
+		return
+	}
+
+	switch left := a.(type) {
+	case map[string]any:
+		right, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+
+		keys := slices.Sorted(maps.Keys(left))
+		for k := range right {
+			if _, found := left[k]; !found {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			l, inLeft := left[k]
+			r, inRight := right[k]
+			child := pointer + "/" + escapePointerToken(k)
+
+			switch {
+			case !inLeft:
+				c.add(child, nil, r, false, true)
+			case !inRight:
+				c.add(child, l, nil, true, false)
+			default:
+				c.compare(child, l, r)
+			}
+		}
+
+		return
+	case []any:
+		right, ok := b.([]any)
+		if !ok {
+			break
+		}
+
+		for i := range max(len(left), len(right)) {
+			child := pointer + "/" + strconv.Itoa(i)
+
+			switch {
+			case i >= len(left):
+				c.add(child, nil, right[i], false, true)
+			case i >= len(right):
+				c.add(child, left[i], nil, true, false)
+			default:
+				c.compare(child, left[i], right[i])
+			}
+		}
+
+		return
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		c.add(pointer, a, b, true, true)
+	}
+}
+
+func (c *comparer) add(pointer string, a, b any, inA, inB bool) {
+	if c.full() {
+		return
+	}
+
+	difference := Difference{Pointer: pointer}
+	if inA {
+		difference.A, _ = json.Marshal(a) //nolint:errchkjson // a generic JSON tree always marshals
+	}
+	if inB {
+		difference.B, _ = json.Marshal(b) //nolint:errchkjson // a generic JSON tree always marshals
+	}
+
+	c.differences = append(c.differences, difference)
+}
@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+const equivalentSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "produces": ["application/json"],
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "type": "string"}],
+      "get": {
+        "description": "get a pet",
+        "parameters": [{"name": "verbose", "in": "query", "type": "boolean"}],
+        "responses": {
+          "200": {"description": "a pet", "schema": {"$ref": "#/definitions/Pet"}}
+        }
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "required": ["id", "name"],
+      "properties": {
+        "id": {"type": "integer"},
+        "name": {"type": "string", "description": "the name"}
+      }
+    }
+  }
+}`
+
+// equivalentRefactored describes the same API as equivalentSpec, written differently.
+const equivalentRefactored = `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {
+    "/pets/{id}": {
+      "get": {
+        "description": "get a pet",
+        "produces": ["application/json"],
+        "parameters": [
+          {"name": "verbose", "in": "query", "type": "boolean"},
+          {"$ref": "#/parameters/id"}
+        ],
+        "responses": {
+          "200": {"$ref": "#/responses/pet"}
+        }
+      }
+    }
+  },
+  "parameters": {
+    "id": {"name": "id", "in": "path", "required": true, "type": "string"}
+  },
+  "responses": {
+    "pet": {"description": "a pet", "schema": {"$ref": "#/definitions/Animal"}}
+  },
+  "definitions": {
+    "Animal": {
+      "properties": {
+        "name": {"description": "the name", "type": "string"},
+        "id": {"type": "integer"}
+      },
+      "required": ["name", "id"],
+      "type": "object"
+    }
+  }
+}`
+
+func TestEquivalent(t *testing.T) {
+	original, err := Analyzed([]byte(equivalentSpec), "")
+	require.NoError(t, err)
+
+	t.Run("should ignore how the spec is written", func(t *testing.T) {
+		refactored, err := Analyzed([]byte(equivalentRefactored), "")
+		require.NoError(t, err)
+
+		equivalent, differences, err := Equivalent(original, refactored)
+		require.NoError(t, err)
+		assert.Empty(t, differences)
+		assert.TrueT(t, equivalent)
+	})
+
+	t.Run("should tell a split spec is equivalent to the original", func(t *testing.T) {
+		document, err := Spec("testdata/json/petstore.json")
+		require.NoError(t, err)
+
+		dir := t.TempDir()
+		files, err := document.Split(dir)
+		require.NoError(t, err)
+
+		split, err := Spec(filepath.Join(dir, files[0]))
+		require.NoError(t, err)
+
+		equivalent, _, err := Equivalent(document, split)
+		require.NoError(t, err)
+		assert.TrueT(t, equivalent)
+	})
+
+	t.Run("should report differences with JSON pointers", func(t *testing.T) {
+		changed, err := original.Modified(func(s *spec.Swagger) error {
+			pet := s.Definitions["Pet"]
+			pet.Properties["id"] = *spec.StringProperty()
+			pet.Properties["tag"] = *spec.StringProperty()
+			s.Definitions["Pet"] = pet
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		equivalent, differences, err := Equivalent(original, changed)
+		require.NoError(t, err)
+		assert.FalseT(t, equivalent)
+		assert.Equal(t, []Difference{
+			{
+				Pointer: "/paths/~1pets~1{id}/get/responses/200/schema/properties/id/type",
+				A:       []byte(`"integer"`),
+				B:       []byte(`"string"`),
+			},
+			{
+				Pointer: "/paths/~1pets~1{id}/get/responses/200/schema/properties/tag",
+				B:       []byte(`{"type":"string"}`),
+			},
+		}, differences)
+		assert.EqualT(t, `/paths/~1pets~1{id}/get/responses/200/schema/properties/tag: only in b: {"type":"string"}`, differences[1].String())
+
+		_, differences, err = Equivalent(original, changed, CompareMaxDifferences(1))
+		require.NoError(t, err)
+		assert.Len(t, differences, 1)
+	})
+
+	t.Run("should optionally ignore the documentation", func(t *testing.T) {
+		documented, err := original.Modified(func(s *spec.Swagger) error {
+			s.Info.Title = "animals"
+			s.Paths.Paths["/pets/{id}"].Get.Description = "fetch a pet"
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		equivalent, differences, err := Equivalent(original, documented)
+		require.NoError(t, err)
+		assert.FalseT(t, equivalent)
+		assert.Len(t, differences, 2)
+
+		equivalent, _, err = Equivalent(original, documented, CompareIgnoreDocs())
+		require.NoError(t, err)
+		assert.TrueT(t, equivalent)
+	})
+}
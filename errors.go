@@ -24,6 +24,34 @@ const (
 	// ErrForbiddenAddress is returned by [RestrictedHTTPClient] when a connection is attempted
 	// to a non-public address (loopback, private, link-local, or unspecified).
 	ErrForbiddenAddress loaderError = "blocked dial to a non-public address"
+
+	// ErrOffline is returned when a remote document is requested in offline mode (see [WithOffline])
+	// and no ref mapping redirects it to a local mirror.
+	ErrOffline loaderError = "remote loading disabled in offline mode"
+
+	// ErrSchemeDisabled is returned when a document is requested with a URI scheme disabled by [WithDisabledSchemes].
+	ErrSchemeDisabled loaderError = "URI scheme disabled"
+
+	// ErrInvalidRef indicates a "$ref" that cannot be resolved.
+	ErrInvalidRef loaderError = "invalid reference"
+
+	// ErrCircularRef indicates a reference cycle, when an expansion is configured to fail on cycles
+	// (see [ExpandCyclesFail]).
+	//
+	// The detailed error is a [*CycleError].
+	ErrCircularRef loaderError = "circular reference"
+
+	// ErrIntegrity indicates that a document does not match the hash pinned in a [Lockfile].
+	//
+	// The detailed error is an [*IntegrityError].
+	ErrIntegrity loaderError = "document integrity check failed"
+
+	// ErrNotPinned is returned by an [IntegrityVerifier] when a document is not listed in its [Lockfile].
+	ErrNotPinned loaderError = "document is not pinned in the lockfile"
+
+	// ErrMergeConflict is returned by [Merge] when documents define a component differently, with the
+	// [MergeFail] strategy. The conflicts are listed by the [MergeReport].
+	ErrMergeConflict loaderError = "merge conflict"
 )
 
 // errLoads marks err as an error from this package, so callers may test it with
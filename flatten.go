@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"github.com/go-openapi/analysis"
+)
+
+// Flattened runs [analysis.Flatten] on a copy of the document and returns a new [Document].
+//
+// The flattening options are the caller's, except that when they leave them unset:
+//   - the base path used to resolve relative references is the path of the document;
+//   - references are resolved through the document's loader, like with [Document.Expanded].
+//
+// The Spec field of the options is ignored: the document itself is flattened. The caller's options
+// are left untouched, and so is the receiver.
+//
+// The [Document.Raw], [Document.Spec] and Analyzer of the returned document all reflect the
+// flattened spec.
+func (d *Document) Flattened(options ...*analysis.FlattenOpts) (*Document, error) {
+	swspec, err := cloneSpec(d.spec)
+	if err != nil {
+		return nil, errLoads(err)
+	}
+
+	var flattenOpts analysis.FlattenOpts
+	if len(options) > 0 && options[0] != nil {
+		flattenOpts = *options[0] // the caller's options are left untouched
+	}
+
+	flattenOpts.Spec = analysis.New(swspec)
+
+	if flattenOpts.BasePath == "" {
+		flattenOpts.BasePath = d.specFilePath
+	}
+
+	if flattenOpts.PathLoaderWithOptions == nil {
+		flattenOpts.PathLoaderWithOptions = d.docLoader().loadWithOptions
+	}
+
+	if err := analysis.Flatten(flattenOpts); err != nil {
+		return nil, err
+	}
+
+	return d.derive(swspec), nil
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/analysis"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestFlattened(t *testing.T) {
+	t.Run("should flatten with the document's loader and base path", func(t *testing.T) {
+		document, err := Spec(mirroredPrefix+"spec.yml",
+			WithRefMapping(mirroredPrefix, "testdata/yaml/swagger"),
+			WithOffline(),
+		)
+		require.NoError(t, err)
+
+		opts := &analysis.FlattenOpts{Minimal: true}
+		flat, err := document.Flattened(opts)
+		require.NoError(t, err)
+
+		b := flat.Spec().Definitions["b"]
+		assert.True(t, b.Type.Contains("array"))
+		assert.EqualT(t, "", b.Ref.String())
+
+		// the caller's options and the receiver are left untouched
+		assert.Nil(t, opts.Spec)
+		assert.Empty(t, opts.BasePath)
+		original := document.Spec().Definitions["b"]
+		assert.EqualT(t, "./test3-ter-model-schema.json#/definitions/b", original.Ref.String())
+
+		// raw, spec and analyzer are consistent
+		fromSpec, err := json.Marshal(flat.Spec())
+		require.NoError(t, err)
+		assert.JSONEqT(t, string(fromSpec), string(flat.Raw()))
+		for _, ref := range flat.Analyzer.AllRefs() {
+			assert.TrueT(t, strings.HasPrefix(ref.String(), "#/definitions/"), ref.String())
+		}
+		assert.EqualT(t, document.SpecFilePath(), flat.SpecFilePath())
+	})
+
+	t.Run("should expand when asked to", func(t *testing.T) {
+		document, err := Spec("testdata/yaml/swagger/spec.yml")
+		require.NoError(t, err)
+
+		flat, err := document.Flattened(&analysis.FlattenOpts{Expand: true})
+		require.NoError(t, err)
+		assert.JSONMarshalAsT(t, cascadeRefExpanded, flat.Spec())
+	})
+
+	t.Run("should fail on unresolvable references", func(t *testing.T) {
+		document, err := Spec(mirroredPrefix+"spec.yml",
+			WithRefMapping(mirroredPrefix, "testdata/yaml/swagger"),
+		)
+		require.NoError(t, err)
+
+		document.pathLoader.refs.offline = true
+		document.pathLoader.refs.mappings = nil
+		_, err = document.Flattened()
+		require.ErrorIs(t, err, ErrOffline)
+	})
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/go-openapi/analysis"
+	"github.com/go-openapi/spec"
+)
+
+// FrozenDocument is a read-only view of a [Document], for documents shared by many goroutines,
+// e.g. a spec loaded once and served by an API gateway.
+//
+// A FrozenDocument cannot be altered, even by accident: it holds a private copy of the document,
+// and its accessors return copies of the spec and raw bytes. All its methods are safe for concurrent use.
+type FrozenDocument struct {
+	doc *Document
+}
+
+// Freeze returns a read-only view of a copy of the document. Later changes to the document do not
+// affect the view.
+func (d *Document) Freeze() *FrozenDocument {
+	return &FrozenDocument{doc: d.Clone()}
+}
+
+// Document returns a new, independent [Document] with the content of the view, which the caller
+// may alter freely.
+func (f *FrozenDocument) Document() *Document {
+	return f.doc.Clone()
+}
+
+// Host returns the host for the API.
+func (f *FrozenDocument) Host() string {
+	return f.doc.Host()
+}
+
+// BasePath the base path for the API specified by this spec.
+func (f *FrozenDocument) BasePath() string {
+	return f.doc.BasePath()
+}
+
+// Version returns the OpenAPI version of this spec (e.g. 2.0).
+func (f *FrozenDocument) Version() string {
+	return f.doc.Version()
+}
+
+// SpecFilePath returns the file path of the spec if one is defined.
+func (f *FrozenDocument) SpecFilePath() string {
+	return f.doc.SpecFilePath()
+}
+
+// Schema returns the swagger 2.0 meta-schema.
+func (f *FrozenDocument) Schema() *spec.Schema {
+	return f.doc.Schema()
+}
+
+// Spec returns a copy of the swagger object model for this API specification.
+//
+// Every call makes a deep copy: callers on a hot path should keep the result, or use the Analyzer.
+func (f *FrozenDocument) Spec() *spec.Swagger {
+	return copySpec(f.doc.spec)
+}
+
+// OrigSpec returns a copy of the original spec.
+func (f *FrozenDocument) OrigSpec() *spec.Swagger {
+	return copySpec(f.doc.OrigSpec())
+}
+
+// Raw returns a copy of the current swagger spec as json bytes.
+func (f *FrozenDocument) Raw() json.RawMessage {
+	return slices.Clone(f.doc.Raw())
+}
+
+// SpecRaw returns a copy of the current swagger spec as json bytes.
+func (f *FrozenDocument) SpecRaw() json.RawMessage {
+	return slices.Clone(f.doc.SpecRaw())
+}
+
+// SourceRaw returns a copy of the swagger spec as json bytes, as it was loaded.
+func (f *FrozenDocument) SourceRaw() json.RawMessage {
+	return slices.Clone(f.doc.SourceRaw())
+}
+
+// Analyzer returns the analysis of the spec.
+//
+// The methods of [analysis.Spec] do not alter it, and may be called concurrently. The maps and
+// operations they return are shared, and must not be altered.
+func (f *FrozenDocument) Analyzer() *analysis.Spec {
+	return f.doc.Analyzer
+}
+
+// LoaderSettings returns the loader configuration of the document.
+func (f *FrozenDocument) LoaderSettings() LoaderSettings {
+	return f.doc.LoaderSettings()
+}
+
+// Expanded expands the "$ref"s of the document, like [Document.Expanded], and returns a new [Document].
+func (f *FrozenDocument) Expanded(options ...*spec.ExpandOptions) (*Document, error) {
+	return f.doc.Expanded(options...)
+}
+
+// ExpandedPartial inlines a selection of the "$ref"s of the document, like [Document.ExpandedPartial],
+// and returns a new [Document].
+func (f *FrozenDocument) ExpandedPartial(opts ...PartialExpandOption) (*Document, error) {
+	return f.doc.ExpandedPartial(opts...)
+}
+
+// Flattened flattens the document, like [Document.Flattened], and returns a new [Document].
+func (f *FrozenDocument) Flattened(options ...*analysis.FlattenOpts) (*Document, error) {
+	return f.doc.Flattened(options...)
+}
+
+// Modified applies fn to a copy of the spec, like [Document.Modified], and returns a new [Document].
+func (f *FrozenDocument) Modified(fn func(*spec.Swagger) error) (*Document, error) {
+	return f.doc.Modified(fn)
+}
+
+// Cycles reports the reference cycles of the document, like [Document.Cycles].
+func (f *FrozenDocument) Cycles() ([]RefCycle, error) {
+	return f.doc.Cycles()
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/go-openapi/analysis"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestFreeze(t *testing.T) {
+	document, err := Spec("testdata/yaml/swagger/spec.yml")
+	require.NoError(t, err)
+
+	frozen := document.Freeze()
+
+	t.Run("should not be affected by changes to the document", func(t *testing.T) {
+		document.Spec().Host = "changed.example.com"
+		t.Cleanup(func() { document.Spec().Host = "api.example.com" })
+
+		assert.EqualT(t, "api.example.com", frozen.Host())
+	})
+
+	t.Run("should not be altered through its accessors", func(t *testing.T) {
+		frozen.Spec().Host = "changed.example.com"
+		frozen.OrigSpec().Host = "changed.example.com"
+		frozen.Raw()[0] = ' '
+		frozen.SourceRaw()[0] = ' '
+		frozen.Document().Spec().Host = "changed.example.com"
+
+		assert.EqualT(t, "api.example.com", frozen.Host())
+		assert.EqualT(t, "api.example.com", frozen.Spec().Host)
+		assert.EqualT(t, "api.example.com", frozen.OrigSpec().Host)
+		assert.Equal(t, document.Raw(), frozen.Raw())
+		assert.Equal(t, document.SourceRaw(), frozen.SourceRaw())
+	})
+
+	t.Run("should derive documents", func(t *testing.T) {
+		expanded, err := frozen.Expanded()
+		require.NoError(t, err)
+		assert.JSONMarshalAsT(t, cascadeRefExpanded, expanded.Spec())
+
+		partial, err := frozen.ExpandedPartial(ExpandExternalOnly())
+		require.NoError(t, err)
+		assert.Len(t, partial.Analyzer.AllRefs(), 2)
+	})
+}
+
+// TestDocumentConcurrentAccess exercises all the accessors and derivations of a shared document
+// concurrently. Run with -race.
+func TestDocumentConcurrentAccess(t *testing.T) {
+	document, err := Spec("testdata/yaml/swagger/spec.yml")
+	require.NoError(t, err)
+	frozen := document.Freeze()
+
+	readDocument := func(t *testing.T, d *Document) {
+		_ = d.Host()
+		_ = d.BasePath()
+		_ = d.Version()
+		_ = d.Schema()
+		_ = d.SpecFilePath()
+		_ = d.Spec().Definitions["a"]
+		_ = d.OrigSpec().Definitions["a"]
+		_ = len(d.Raw()) + len(d.SourceRaw())
+		_ = d.LoaderSettings()
+		_ = d.LoaderOptions()
+		readAnalyzer(d.Analyzer)
+
+		_, err := d.Loader()("testdata/yaml/swagger/test3-ter-model-schema.json")
+		assert.NoError(t, err)
+
+		derived := []func() (*Document, error){
+			func() (*Document, error) { return d.Expanded() },
+			func() (*Document, error) { return d.ExpandedPartial(ExpandExternalOnly()) },
+			func() (*Document, error) { return d.Flattened(&analysis.FlattenOpts{Minimal: true}) },
+			func() (*Document, error) { return d.Pristine(), nil },
+			func() (*Document, error) { return d.ResetDefinitions(), nil },
+			func() (*Document, error) { return d.Clone(), nil },
+			func() (*Document, error) { return d.Freeze().Document(), nil },
+			func() (*Document, error) {
+				return d.Modified(func(sp *spec.Swagger) error {
+					sp.Host = "modified.example.com"
+
+					return nil
+				})
+			},
+		}
+		for _, derive := range derived {
+			dd, err := derive()
+			assert.NoError(t, err)
+			readAnalyzer(dd.Analyzer)
+		}
+
+		_, err = d.Cycles()
+		assert.NoError(t, err)
+
+		_, err = d.Vendor(t.TempDir())
+		assert.NoError(t, err)
+	}
+
+	readFrozen := func(t *testing.T, f *FrozenDocument) {
+		_ = f.Host()
+		_ = f.BasePath()
+		_ = f.Version()
+		_ = f.Schema()
+		_ = f.SpecFilePath()
+		_ = f.Spec()
+		_ = f.OrigSpec()
+		_ = len(f.Raw()) + len(f.SourceRaw())
+		_ = f.LoaderSettings()
+		readAnalyzer(f.Analyzer())
+
+		_, err := f.Expanded()
+		assert.NoError(t, err)
+		_, err = f.ExpandedPartial()
+		assert.NoError(t, err)
+		_, err = f.Flattened()
+		assert.NoError(t, err)
+		_, err = f.Modified(func(*spec.Swagger) error { return nil })
+		assert.NoError(t, err)
+		_, err = f.Cycles()
+		assert.NoError(t, err)
+		_ = f.Document()
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() { readDocument(t, document) })
+		wg.Go(func() { readFrozen(t, frozen) })
+	}
+	wg.Wait()
+
+	assert.EqualT(t, "api.example.com", document.Host())
+	b := document.Spec().Definitions["b"]
+	assert.EqualT(t, "./test3-ter-model-schema.json#/definitions/b", b.Ref.String())
+}
+
+func readAnalyzer(a *analysis.Spec) {
+	_ = a.AllRefs()
+	_ = a.AllDefinitions()
+	_ = a.AllPaths()
+	_ = a.OperationIDs()
+	_ = a.RequiredConsumes()
+	_ = a.RequiredProduces()
+	_ = a.AllPatterns()
+	_ = a.AllEnums()
+	if op, ok := a.OperationFor("GET", "/getAll"); ok {
+		_ = a.ParamsFor("GET", "/getAll")
+		_ = a.SecurityRequirementsFor(op)
+		_ = a.ConsumesFor(op)
+	}
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+)
+
+// SpecFromBytes loads a new spec document from data, as if it had been loaded from baseURI.
+//
+// data may be JSON or YAML. baseURI is the virtual location of the document itself (e.g.
+// "https://api.example.com/specs/pets.json" or "specs/pets.yaml"), not of its folder: relative
+// "$ref"s are resolved against it by the loader configured by opts, like for a document loaded with
+// [Spec]. A reference back to baseURI resolves to data, so the document is never fetched.
+//
+// When baseURI is empty, SpecFromBytes is equivalent to [Analyzed]: relative references are
+// resolved against the current working directory.
+func SpecFromBytes(data []byte, baseURI string, opts ...LoaderOption) (*Document, error) {
+	document, err := Analyzed(data, "", opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if baseURI == "" {
+		return document, nil
+	}
+
+	document.specFilePath = baseURI
+	document.pathLoader = loaderFromOptions(append(slices.Clone(opts), withVirtualDocument(baseURI, document.Raw())))
+
+	return document, nil
+}
+
+// SpecFromReader loads a new spec document read from r, e.g. the body of an HTTP request, as if it
+// had been loaded from baseURI. See [SpecFromBytes].
+//
+// The document is read entirely. For large JSON documents, see [AnalyzedStream].
+func SpecFromReader(r io.Reader, baseURI string, opts ...LoaderOption) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errLoads(err)
+	}
+
+	return SpecFromBytes(data, baseURI, opts...)
+}
+
+// withVirtualDocument serves data for the document at location, without loading it.
+func withVirtualDocument(location string, data json.RawMessage) LoaderOption {
+	return func(opt *options) {
+		virtual := maps.Clone(opt.virtual)
+		if virtual == nil {
+			virtual = make(map[string]json.RawMessage, 1)
+		}
+		virtual[lockKey(location)] = data
+		opt.virtual = virtual
+	}
+}
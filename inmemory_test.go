@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestSpecFromBytes(t *testing.T) {
+	data, err := os.ReadFile("testdata/yaml/swagger/spec.yml")
+	require.NoError(t, err)
+
+	t.Run("should resolve references against a remote virtual base", func(t *testing.T) {
+		var rootHits atomic.Int32
+		files := http.FileServer(http.Dir("testdata/yaml/swagger"))
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/specs/spec.yml" {
+				rootHits.Add(1)
+			}
+			http.StripPrefix("/specs", files).ServeHTTP(rw, r)
+		}))
+		defer srv.Close()
+
+		document, err := SpecFromBytes(data, srv.URL+"/specs/spec.yml")
+		require.NoError(t, err)
+		assert.EqualT(t, srv.URL+"/specs/spec.yml", document.SpecFilePath())
+
+		expanded, err := document.Expanded()
+		require.NoError(t, err)
+		assert.JSONMarshalAsT(t, cascadeRefExpanded, expanded.Spec())
+		assert.EqualT(t, int32(0), rootHits.Load())
+	})
+
+	t.Run("should resolve references against a local virtual base", func(t *testing.T) {
+		document, err := SpecFromReader(bytes.NewReader(data), "testdata/yaml/swagger/virtual.yml")
+		require.NoError(t, err)
+
+		expanded, err := document.Expanded()
+		require.NoError(t, err)
+		assert.JSONMarshalAsT(t, cascadeRefExpanded, expanded.Spec())
+	})
+
+	t.Run("should resolve references through the configured loader", func(t *testing.T) {
+		document, err := SpecFromBytes(data, mirroredPrefix+"virtual.yml",
+			WithRefMapping(mirroredPrefix, "testdata/yaml/swagger"),
+			WithOffline(),
+		)
+		require.NoError(t, err)
+
+		expanded, err := document.Expanded()
+		require.NoError(t, err)
+		assert.JSONMarshalAsT(t, cascadeRefExpanded, expanded.Spec())
+
+		_, err = document.Loader()("https://example.com/other.json")
+		require.ErrorIs(t, err, ErrOffline)
+	})
+
+	t.Run("should resolve references back to the virtual document", func(t *testing.T) {
+		// the root document only exists in memory, next to the document it refers to
+		dir := filepath.ToSlash(t.TempDir())
+		other, err := os.ReadFile("testdata/json/cycles/other.json")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "other.json"), other, 0o600))
+		root, err := os.ReadFile(cyclicFixture)
+		require.NoError(t, err)
+		pth := dir + "/spec.json"
+
+		document, err := SpecFromBytes(root, pth)
+		require.NoError(t, err)
+
+		cycles, err := document.Cycles()
+		require.NoError(t, err)
+		assert.Len(t, cycles, 2)
+
+		_, err = document.Expanded()
+		require.NoError(t, err)
+
+		lock, err := document.Vendor(t.TempDir())
+		require.NoError(t, err)
+		assert.Len(t, lock.Documents, 2)
+
+		// derived documents keep the virtual document
+		pristine := document.Pristine()
+		_, err = pristine.Cycles()
+		require.NoError(t, err)
+		assert.FileNotExists(t, pth)
+	})
+
+	t.Run("should behave like Analyzed without a base", func(t *testing.T) {
+		document, err := SpecFromBytes(petStoreJSON, "")
+		require.NoError(t, err)
+		assert.Empty(t, document.SpecFilePath())
+		assert.JSONEqT(t, string(petStoreJSON), string(document.Raw()))
+	})
+
+	t.Run("should report read errors", func(t *testing.T) {
+		_, err := SpecFromReader(iotest.ErrReader(errTest), "spec.json")
+		require.ErrorIs(t, err, errTest)
+		require.ErrorIs(t, err, ErrLoads)
+
+		_, err = SpecFromBytes([]byte(`{"swagger":`), "spec.json")
+		require.ErrorIs(t, err, ErrLoads)
+	})
+}
+
+func TestVirtualDocument(t *testing.T) {
+	ldr := loaderFromOptions([]LoaderOption{withVirtualDocument("specs/root.json", []byte(`{}`))})
+
+	b, err := ldr.Load(filepath.Join("specs", ".", "root.json"))
+	require.NoError(t, err)
+	assert.EqualT(t, "{}", string(b))
+
+	_, err = ldr.Load("specs/other.json")
+	require.Error(t, err)
+}
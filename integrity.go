@@ -0,0 +1,249 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/go-openapi/swag/loading"
+)
+
+// IntegrityError reports a document whose content does not match the SHA-256 pinned in a [Lockfile].
+//
+// It matches [ErrIntegrity] and [ErrLoads] with [errors.Is].
+type IntegrityError struct {
+	URL      string
+	Expected string
+	Actual   string
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("%s: %s: %q: expected sha256 %s, got %s", ErrLoads, ErrIntegrity, e.URL, e.Expected, e.Actual)
+}
+
+// Unwrap allows [errors.Is] to match [ErrIntegrity] and [ErrLoads].
+func (e *IntegrityError) Unwrap() []error {
+	return []error{ErrLoads, ErrIntegrity}
+}
+
+// IntegrityOption configures an [IntegrityVerifier].
+type IntegrityOption func(*IntegrityVerifier)
+
+// WithLockfileUpdate switches the verifier to update mode: instead of verifying fetched documents,
+// it pins their current hash, adding or replacing entries in the lockfile.
+//
+// Use it to generate or regenerate a lockfile, then save it with [Lockfile.WriteFile].
+func WithLockfileUpdate() IntegrityOption {
+	return func(v *IntegrityVerifier) {
+		v.update = true
+	}
+}
+
+// WithUnpinnedAllowed lets documents that are not listed in the lockfile load without verification.
+//
+// By default, loading an unpinned document fails with [ErrNotPinned].
+func WithUnpinnedAllowed() IntegrityOption {
+	return func(v *IntegrityVerifier) {
+		v.allowUnpinned = true
+	}
+}
+
+// IntegrityVerifier checks every document fetched by a loader against the SHA-256 pinned in a [Lockfile].
+//
+// With the built-in loaders (see [DefaultLoaders], [SetRestrictedLoaders] and [RestrictedRootsLoaders]),
+// the hash covers the raw bytes of a document, as fetched (e.g. the YAML source, before its conversion
+// to JSON), so that it matches the output of sha256sum. They convert the very bytes that were verified,
+// without fetching them again. A document served by a custom loader (including a scheme loader, see
+// [WithSchemeLoader]) is hashed as returned by that loader.
+//
+// The simplest way to use it is [WithIntegrity], which verifies every document loaded by the loader of
+// a document, pinned under its location as referenced, before any ref mapping.
+//
+// It also wraps [DocLoader]s, so it can be used with [WithDocLoader], [WithDocLoaderMatches] and
+// [SetLoaders]. To verify the documents loaded by [SpecRestricted] (which relies on the package-level
+// loaders), install the wrapped default chain with:
+//
+//	loads.SetLoaders(verifier.WrapMatches(loads.DefaultLoaders()...)...)
+//
+// A wrapped loader sees the path it is called with: under a [WithRefMapping], this is the path inside
+// the mirror, not the original URL. Use [WithIntegrity] to verify mapped documents.
+//
+// An IntegrityVerifier is safe for concurrent use.
+type IntegrityVerifier struct {
+	mu            sync.Mutex
+	lock          *Lockfile
+	update        bool
+	allowUnpinned bool
+}
+
+// NewIntegrityVerifier builds an [IntegrityVerifier] for lock.
+//
+// A nil lock is an empty lockfile, which is mostly useful in update mode (see [WithLockfileUpdate]).
+func NewIntegrityVerifier(lock *Lockfile, opts ...IntegrityOption) *IntegrityVerifier {
+	if lock == nil {
+		lock = &Lockfile{Version: lockfileVersion}
+	}
+
+	v := &IntegrityVerifier{lock: lock}
+	for _, apply := range opts {
+		apply(v)
+	}
+
+	return v
+}
+
+// WithIntegrity verifies (or, in update mode, pins) every document loaded by the document's loader
+// with the verifier.
+//
+// Documents are pinned under their location as referenced: with [WithRefMapping], a mapped document is
+// looked up in the lockfile by its original URL, not by its path inside the mirror.
+func WithIntegrity(v *IntegrityVerifier) LoaderOption {
+	return func(opt *options) {
+		opt.integrity = v
+	}
+}
+
+// Wrap returns a [DocLoader] that verifies (or, in update mode, pins) every document loaded by fn.
+//
+// A wrapped loader can only verify what fn returns: this is the raw document for [JSONDoc], but the
+// converted JSON for a YAML loader. Use [IntegrityVerifier.WrapMatches] or [WithIntegrity] to verify
+// YAML documents as fetched.
+func (v *IntegrityVerifier) Wrap(fn DocLoader) DocLoader {
+	if fn == nil {
+		return nil
+	}
+
+	return func(pth string, opts ...loading.Option) (json.RawMessage, error) {
+		return v.load(pth, pth, opts, DocLoaderWithMatch{Fn: fn})
+	}
+}
+
+// load loads the document at pth with ldr, and verifies it against the entry pinned for location.
+func (v *IntegrityVerifier) load(location, pth string, opts []loading.Option, ldr DocLoaderWithMatch) (json.RawMessage, error) {
+	data, err := v.fetch(location, pth, opts, ldr)
+	if err != nil {
+		return nil, err
+	}
+
+	if ldr.fetch == nil {
+		return data, nil
+	}
+
+	return ldr.convert(data)
+}
+
+// fetch fetches the document at pth with ldr, unconverted, and verifies it against the entry pinned
+// for location.
+//
+// The built-in loaders are verified on the raw bytes they fetch, before their conversion to JSON.
+// Other loaders are verified on what they return.
+func (v *IntegrityVerifier) fetch(location, pth string, opts []loading.Option, ldr DocLoaderWithMatch) (json.RawMessage, error) {
+	fetch := ldr.fetch
+	if fetch == nil {
+		fetch = ldr.Fn
+	}
+
+	data, err := fetch(pth, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.check(location, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// WrapMatches wraps the loading function of every [DocLoaderWithMatch], keeping their matchers.
+func (v *IntegrityVerifier) WrapMatches(ldrs ...DocLoaderWithMatch) []DocLoaderWithMatch {
+	wrapped := make([]DocLoaderWithMatch, 0, len(ldrs))
+	for _, ldr := range ldrs {
+		if ldr.Fn == nil {
+			wrapped = append(wrapped, ldr)
+
+			continue
+		}
+
+		wrapped = append(wrapped, v.wrapMatch(ldr))
+	}
+
+	return wrapped
+}
+
+// wrapMatch wraps ldr, as well as the loader it confines to the mirror of a ref mapping, if any.
+//
+// The wrapped loader still fetches raw documents like ldr, verified before their conversion.
+func (v *IntegrityVerifier) wrapMatch(ldr DocLoaderWithMatch) DocLoaderWithMatch {
+	fn := func(pth string, opts ...loading.Option) (json.RawMessage, error) {
+		return v.load(pth, pth, opts, ldr)
+	}
+
+	wrapped := NewDocLoaderWithMatch(fn, ldr.Match)
+	if ldr.fetch != nil {
+		wrapped.fetch = func(pth string, opts ...loading.Option) (json.RawMessage, error) {
+			return v.fetch(pth, pth, opts, ldr)
+		}
+		wrapped.convert = ldr.convert
+	}
+	if ldr.mirrored != nil {
+		wrapped.mirrored = func(mapping RefMapping) (DocLoaderWithMatch, error) {
+			mirrored, err := ldr.mirrored(mapping)
+			if err != nil {
+				return DocLoaderWithMatch{}, err
+			}
+
+			return v.wrapMatch(mirrored), nil
+		}
+	}
+
+	return wrapped
+}
+
+// Lockfile returns a copy of the current lockfile, including the entries pinned in update mode.
+func (v *IntegrityVerifier) Lockfile() *Lockfile {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return &Lockfile{
+		Version:   v.lock.Version,
+		Root:      v.lock.Root,
+		Documents: slices.Clone(v.lock.Documents),
+	}
+}
+
+func (v *IntegrityVerifier) check(pth string, data []byte) error {
+	actual := hashOf(data)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.update {
+		key := lockKey(pth)
+		entry, _ := v.lock.Lookup(key)
+		entry.URL = key
+		entry.SHA256 = actual
+		v.lock.set(entry)
+
+		return nil
+	}
+
+	entry, ok := v.lock.Lookup(pth)
+	if !ok {
+		if v.allowUnpinned {
+			return nil
+		}
+
+		return errLoads(fmt.Errorf("%w: %q", ErrNotPinned, pth))
+	}
+
+	if entry.SHA256 != actual {
+		return &IntegrityError{URL: entry.URL, Expected: entry.SHA256, Actual: actual}
+	}
+
+	return nil
+}
@@ -0,0 +1,232 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+// copyFixture copies the cascading ref fixture into a temporary directory, so it may be tampered with.
+func copyFixture(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, name := range []string{"spec.yml", "test3-ter-model-schema.json"} {
+		b, err := os.ReadFile(filepath.Join("testdata", "yaml", "swagger", name))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), b, 0o600))
+	}
+
+	return dir
+}
+
+// pinFixture generates a lockfile for the spec in dir, using a verifier in update mode.
+func pinFixture(t *testing.T, dir string) *loads.Lockfile {
+	t.Helper()
+
+	recorder := loads.NewIntegrityVerifier(nil, loads.WithLockfileUpdate())
+	doc, err := loads.Spec(filepath.Join(dir, "spec.yml"), loads.WithDocLoaderMatches(recorder.WrapMatches(loads.DefaultLoaders()...)...))
+	require.NoError(t, err)
+
+	_, err = doc.Expanded()
+	require.NoError(t, err)
+
+	lock := recorder.Lockfile()
+	require.Len(t, lock.Documents, 2)
+
+	return lock
+}
+
+func TestIntegrityVerifier(t *testing.T) {
+	t.Run("should regenerate a lockfile and verify against it", func(t *testing.T) {
+		dir := copyFixture(t)
+		lock := pinFixture(t, dir)
+
+		pth := filepath.Join(t.TempDir(), loads.LockfileName)
+		require.NoError(t, lock.WriteFile(pth))
+		onDisk, err := loads.ReadLockfile(pth)
+		require.NoError(t, err)
+
+		verifier := loads.NewIntegrityVerifier(onDisk)
+		doc, err := loads.Spec(filepath.Join(dir, "spec.yml"), loads.WithDocLoaderMatches(verifier.WrapMatches(loads.DefaultLoaders()...)...))
+		require.NoError(t, err)
+
+		_, err = doc.Expanded()
+		require.NoError(t, err)
+	})
+
+	t.Run("should agree with the lockfile written by Document.Vendor", func(t *testing.T) {
+		dir := copyFixture(t)
+		doc, err := loads.Spec(filepath.Join(dir, "spec.yml"))
+		require.NoError(t, err)
+
+		lock, err := doc.Vendor(t.TempDir())
+		require.NoError(t, err)
+
+		verifier := loads.NewIntegrityVerifier(lock)
+		doc, err = loads.Spec(filepath.Join(dir, "spec.yml"), loads.WithDocLoaderMatches(verifier.WrapMatches(loads.DefaultLoaders()...)...))
+		require.NoError(t, err)
+
+		_, err = doc.Expanded()
+		require.NoError(t, err)
+	})
+
+	t.Run("should fail with a typed error when a referenced document changed", func(t *testing.T) {
+		dir := copyFixture(t)
+		lock := pinFixture(t, dir)
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "test3-ter-model-schema.json"),
+			[]byte(`{"definitions":{"b":{"type":"integer"}}}`), 0o600))
+
+		verifier := loads.NewIntegrityVerifier(lock)
+		doc, err := loads.Spec(filepath.Join(dir, "spec.yml"), loads.WithDocLoaderMatches(verifier.WrapMatches(loads.DefaultLoaders()...)...))
+		require.NoError(t, err) // the root is unchanged
+
+		_, err = doc.Expanded()
+		require.Error(t, err)
+		require.ErrorIs(t, err, loads.ErrIntegrity)
+		require.ErrorIs(t, err, loads.ErrLoads)
+
+		var integrityErr *loads.IntegrityError
+		require.True(t, errors.As(err, &integrityErr))
+		assert.StringContainsT(t, integrityErr.URL, "test3-ter-model-schema.json")
+		assert.NotEqual(t, integrityErr.Expected, integrityErr.Actual)
+	})
+
+	t.Run("should fetch raw streams through wrapped loaders", func(t *testing.T) {
+		pth := filepath.Join(t.TempDir(), "specs.yaml")
+		stream := []byte(`swagger: "2.0"
+info: {title: first, version: "1"}
+paths: {}
+---
+swagger: "2.0"
+info: {title: second, version: "1"}
+paths: {}
+`)
+		require.NoError(t, os.WriteFile(pth, stream, 0o600))
+
+		recorder := loads.NewIntegrityVerifier(nil, loads.WithLockfileUpdate())
+		docs, err := loads.SpecCollection(pth, loads.WithDocLoaderMatches(recorder.WrapMatches(loads.DefaultLoaders()...)...))
+		require.NoError(t, err)
+		require.Len(t, docs, 2)
+		assert.EqualT(t, "second", docs[1].Spec().Info.Title)
+
+		lock := recorder.Lockfile()
+		require.Len(t, lock.Documents, 1)
+		sum := sha256.Sum256(stream)
+		assert.EqualT(t, hex.EncodeToString(sum[:]), lock.Documents[0].SHA256)
+	})
+
+	t.Run("should reject unpinned documents unless allowed", func(t *testing.T) {
+		dir := copyFixture(t)
+
+		verifier := loads.NewIntegrityVerifier(&loads.Lockfile{})
+		_, err := loads.Spec(filepath.Join(dir, "spec.yml"), loads.WithDocLoader(verifier.Wrap(loads.JSONDoc)))
+		require.ErrorIs(t, err, loads.ErrNotPinned)
+
+		permissive := loads.NewIntegrityVerifier(&loads.Lockfile{}, loads.WithUnpinnedAllowed())
+		_, err = loads.Spec(filepath.Join(dir, "spec.yml"), loads.WithDocLoader(permissive.Wrap(loads.JSONDoc)))
+		require.NoError(t, err)
+	})
+}
+
+func TestWithIntegrity(t *testing.T) {
+	const prefix = "https://example.com/specs/"
+	dir := copyFixture(t)
+
+	recorder := loads.NewIntegrityVerifier(nil, loads.WithLockfileUpdate())
+	load := func(verifier *loads.IntegrityVerifier) error {
+		doc, err := loads.Spec(prefix+"spec.yml", loads.WithRefMapping(prefix, dir), loads.WithOffline(), loads.WithIntegrity(verifier))
+		if err != nil {
+			return err
+		}
+
+		_, err = doc.Expanded()
+
+		return err
+	}
+	require.NoError(t, load(recorder))
+	lock := recorder.Lockfile()
+
+	t.Run("should pin mapped documents by their original URL", func(t *testing.T) {
+		require.Len(t, lock.Documents, 2)
+		assert.EqualT(t, prefix+"spec.yml", lock.Documents[0].URL)
+		assert.EqualT(t, prefix+"test3-ter-model-schema.json", lock.Documents[1].URL)
+	})
+
+	t.Run("should hash the raw bytes of documents", func(t *testing.T) {
+		source, err := os.ReadFile(filepath.Join(dir, "spec.yml"))
+		require.NoError(t, err)
+
+		sum := sha256.Sum256(source)
+		assert.EqualT(t, hex.EncodeToString(sum[:]), lock.Documents[0].SHA256, "the hash should match sha256sum of the YAML source")
+	})
+
+	t.Run("should verify mapped documents", func(t *testing.T) {
+		require.NoError(t, load(loads.NewIntegrityVerifier(lock)))
+
+		pth := filepath.Join(dir, "test3-ter-model-schema.json")
+		data, err := os.ReadFile(pth)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(pth, append(data, '\n'), 0o600))
+
+		require.ErrorIs(t, load(loads.NewIntegrityVerifier(lock)), loads.ErrIntegrity)
+	})
+}
+
+func TestIntegrityVerifierWithSpecRestricted(t *testing.T) {
+	t.Cleanup(func() { loads.SetLoaders() }) // restore the built-in default
+
+	dir := copyFixture(t)
+	lock := pinFixture(t, dir)
+
+	verifier := loads.NewIntegrityVerifier(lock)
+	loads.SetLoaders(verifier.WrapMatches(loads.DefaultLoaders()...)...)
+
+	t.Run("should verify documents loaded by SpecRestricted", func(t *testing.T) {
+		doc, err := loads.SpecRestricted(filepath.Join(dir, "spec.yml"), dir)
+		require.NoError(t, err)
+
+		_, err = doc.Expanded()
+		require.NoError(t, err)
+	})
+
+	t.Run("should fail SpecRestricted on a tampered document", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "spec.yml"), []byte("swagger: '2.0'\ninfo: {title: t, version: '1'}\npaths: {}\n"), 0o600))
+
+		_, err := loads.SpecRestricted(filepath.Join(dir, "spec.yml"), dir)
+		require.ErrorIs(t, err, loads.ErrIntegrity)
+	})
+}
+
+func TestIntegrityVerifierWithRestrictedRoots(t *testing.T) {
+	dir := copyFixture(t)
+	outside := copyFixture(t)
+
+	verifier := loads.NewIntegrityVerifier(nil, loads.WithLockfileUpdate())
+	ldrs := verifier.WrapMatches(loads.RestrictedRootsLoaders([]string{dir}, nil)...)
+
+	t.Run("should hash the documents within the roots", func(t *testing.T) {
+		_, err := loads.Spec(filepath.Join(dir, "spec.yml"), loads.WithDocLoaderMatches(ldrs...))
+		require.NoError(t, err)
+	})
+
+	t.Run("should not fetch documents outside the roots", func(t *testing.T) {
+		_, err := loads.Spec(filepath.Join(outside, "spec.yml"), loads.WithDocLoaderMatches(ldrs...))
+		require.Error(t, err)
+
+		lock := verifier.Lockfile()
+		require.Len(t, lock.Documents, 1)
+		assert.StringContainsT(t, lock.Documents[0].URL, filepath.ToSlash(dir))
+	})
+}
@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads_test
+
+import (
+	"encoding/json"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag/loading"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+// These tests replace the package-level and spec-package global loaders with tripwires, to prove
+// that a document configured with its own loader never reaches them. They mutate globals: they
+// must not run in parallel.
+
+const isolationFixture = "testdata/yaml/swagger/spec.yml"
+
+// installTripwires points both global loaders at a loader failing the test, restoring the defaults on cleanup.
+func installTripwires(t *testing.T) {
+	t.Helper()
+
+	previous := spec.PathLoader
+	t.Cleanup(func() {
+		loads.SetLoaders()
+		spec.PathLoader = previous
+	})
+
+	tripwire := func(pth string, _ ...loading.Option) (json.RawMessage, error) {
+		t.Errorf("global loader reached for %q", pth)
+
+		return nil, os.ErrPermission
+	}
+
+	loads.SetLoaders(loads.NewDocLoaderWithMatch(tripwire, nil))
+	spec.PathLoader = func(pth string) (json.RawMessage, error) {
+		return tripwire(pth)
+	}
+}
+
+// countingLoader is a per-document loader that counts its calls.
+func countingLoader(calls *atomic.Int32) loads.DocLoader {
+	return func(pth string, opts ...loading.Option) (json.RawMessage, error) {
+		calls.Add(1)
+
+		return loads.LoaderChain(loads.DefaultLoaders()...)(pth, opts...)
+	}
+}
+
+func TestPerDocumentResolution(t *testing.T) {
+	installTripwires(t)
+
+	load := map[string]func(loads.LoaderOption) (*loads.Document, error){
+		"Spec": func(opt loads.LoaderOption) (*loads.Document, error) {
+			return loads.Spec(isolationFixture, opt)
+		},
+		"Registry.Spec": func(opt loads.LoaderOption) (*loads.Document, error) {
+			return loads.NewRegistry(opt).Spec(isolationFixture)
+		},
+		"JSONSpec": func(opt loads.LoaderOption) (*loads.Document, error) {
+			return loads.JSONSpec("testdata/yaml/swagger/test3-ter-model-schema.json", opt)
+		},
+	}
+
+	derive := map[string]func(*loads.Document) (*loads.Document, error){
+		"as loaded": func(d *loads.Document) (*loads.Document, error) {
+			return d, nil
+		},
+		"Expanded": func(d *loads.Document) (*loads.Document, error) {
+			return d.Expanded()
+		},
+		"Pristine": func(d *loads.Document) (*loads.Document, error) {
+			return d.Pristine(), nil
+		},
+		"ResetDefinitions": func(d *loads.Document) (*loads.Document, error) {
+			return d.ResetDefinitions(), nil
+		},
+		"Expanded with options": func(d *loads.Document) (*loads.Document, error) {
+			return d.Expanded(&spec.ExpandOptions{SkipSchemas: true})
+		},
+	}
+
+	for loadName, loadFn := range load {
+		for deriveName, deriveFn := range derive {
+			t.Run(loadName+"/"+deriveName, func(t *testing.T) {
+				var calls atomic.Int32
+				doc, err := loadFn(loads.WithDocLoader(countingLoader(&calls)))
+				require.NoError(t, err)
+
+				derived, err := deriveFn(doc)
+				require.NoError(t, err)
+
+				before := calls.Load()
+				_, err = derived.Expanded()
+				require.NoError(t, err)
+
+				// the JSONSpec fixture has no remote $ref, and an expanded document has none left
+				if loadName != "JSONSpec" && deriveName != "Expanded" {
+					assert.Greater(t, calls.Load(), before, "expected the per-document loader to resolve references")
+				}
+			})
+		}
+	}
+
+	t.Run("Analyzed", func(t *testing.T) {
+		raw, err := os.ReadFile("testdata/json/petstore.json")
+		require.NoError(t, err)
+
+		var calls atomic.Int32
+		doc, err := loads.Analyzed(raw, "", loads.WithDocLoader(countingLoader(&calls)))
+		require.NoError(t, err)
+
+		_, err = doc.Expanded()
+		require.NoError(t, err)
+	})
+
+	t.Run("Embedded", func(t *testing.T) {
+		doc, err := loads.Spec(isolationFixture, loads.WithDocLoader(countingLoader(new(atomic.Int32))))
+		require.NoError(t, err)
+
+		var calls atomic.Int32
+		embedded, err := loads.Embedded(doc.Raw(), doc.Raw(), loads.WithDocLoader(countingLoader(&calls)))
+		require.NoError(t, err)
+
+		_, err = embedded.Expanded(&spec.ExpandOptions{RelativeBase: isolationFixture})
+		require.NoError(t, err)
+		assert.Positive(t, calls.Load())
+	})
+
+	t.Run("should leave the caller's expand options untouched", func(t *testing.T) {
+		doc, err := loads.Spec(isolationFixture, loads.WithDocLoader(countingLoader(new(atomic.Int32))))
+		require.NoError(t, err)
+
+		opts := &spec.ExpandOptions{}
+		_, err = doc.Expanded(opts)
+		require.NoError(t, err)
+		assert.Empty(t, opts.RelativeBase)
+		assert.Nil(t, opts.PathLoader)
+	})
+}
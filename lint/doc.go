@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lint checks swagger (OAI v2) specs loaded with [github.com/go-openapi/loads] against
+// style rules, e.g. naming conventions and documentation requirements.
+//
+// A [Rule] inspects a [loads.Document] (its raw JSON, its spec and its analyzer) and reports
+// [Finding]s, located by a JSON pointer and by their [Position] in the source of the document. For a
+// document loaded from YAML, pass the YAML bytes with [WithSource] to get positions in the YAML file.
+//
+// [Lint] checks the recommended built-in rules by default (see [RecommendedRules]). A [Ruleset],
+// usually loaded from a YAML or JSON file with [LoadRuleset], selects the rules and their severity:
+//
+//	extends: recommended   # or "all", or "none"
+//	rules:
+//	  operation-tags: off
+//	  operation-description: error
+//	  property-camel-case: warning
+//
+// Custom rules are added with [WithRules], and configured by the ruleset like the built-in ones.
+package lint
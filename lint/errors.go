@@ -0,0 +1,13 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+type lintError string
+
+func (e lintError) Error() string {
+	return string(e)
+}
+
+// ErrRuleset indicates an invalid [Ruleset].
+const ErrRuleset lintError = "invalid ruleset"
@@ -0,0 +1,292 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/analysis"
+	"github.com/go-openapi/spec"
+
+	"github.com/go-openapi/loads"
+)
+
+// Severity is the level of a [Finding].
+type Severity int
+
+const (
+	// Off disables a rule.
+	Off Severity = iota
+	// Hint is a suggestion.
+	Hint
+	// Info is a finding worth knowing about.
+	Info
+	// Warning is a finding that should be fixed.
+	Warning
+	// Error is a finding that must be fixed.
+	Error
+)
+
+var severityNames = map[Severity]string{
+	Off:     "off",
+	Hint:    "hint",
+	Info:    "info",
+	Warning: "warning",
+	Error:   "error",
+}
+
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// ParseSeverity parses the name of a severity: "off", "hint", "info", "warning" (or "warn") or "error".
+func ParseSeverity(name string) (Severity, error) {
+	lower := strings.ToLower(strings.TrimSpace(name))
+	if lower == "warn" {
+		return Warning, nil
+	}
+
+	for s, n := range severityNames {
+		if n == lower {
+			return s, nil
+		}
+	}
+
+	return Off, fmt.Errorf("%w: unknown severity %q", ErrRuleset, name)
+}
+
+// MarshalText writes the name of the severity.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText parses the name of a severity, see [ParseSeverity].
+func (s *Severity) UnmarshalText(text []byte) error {
+	parsed, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+
+	return nil
+}
+
+// Position is a location in the source of a document. Lines and columns start at 1: a zero
+// position is unknown.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// IsValid tells if the position is known.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// Finding is a rule violation found in a document.
+type Finding struct {
+	// Rule is the name of the rule.
+	Rule string
+
+	// Severity is the severity of the rule, as configured.
+	Severity Severity
+
+	// Message describes the violation.
+	Message string
+
+	// Pointer is the JSON pointer of the offending node in the spec.
+	Pointer string
+
+	// Position is the position of the offending node in the source of the document, or of its closest
+	// parent when the node itself is not in the source.
+	Position Position
+}
+
+func (f Finding) String() string {
+	location := f.Pointer
+	if location == "" {
+		location = "/"
+	}
+	if f.Position.IsValid() {
+		location = f.Position.String() + " " + location
+	}
+
+	return fmt.Sprintf("%s: %s: %s [%s]", location, f.Severity, f.Message, f.Rule)
+}
+
+// Rule checks a document.
+type Rule interface {
+	// Name identifies the rule, e.g. in a [Ruleset].
+	Name() string
+
+	// Description tells what the rule checks.
+	Description() string
+
+	// Severity is the default severity of the findings of the rule.
+	Severity() Severity
+
+	// Check reports the violations found in the document of ctx with [Context.Report].
+	Check(ctx *Context)
+}
+
+// NewRule builds a [Rule] from a function.
+func NewRule(name, description string, severity Severity, check func(*Context)) Rule {
+	return funcRule{name: name, description: description, severity: severity, check: check}
+}
+
+type funcRule struct {
+	name        string
+	description string
+	severity    Severity
+	check       func(*Context)
+}
+
+func (r funcRule) Name() string        { return r.name }
+func (r funcRule) Description() string { return r.description }
+func (r funcRule) Severity() Severity  { return r.severity }
+func (r funcRule) Check(ctx *Context)  { r.check(ctx) }
+
+// Context gives a rule access to the document being linted, and collects its findings.
+type Context struct {
+	document *loads.Document
+	rule     Rule
+	severity Severity
+	findings []Finding
+}
+
+// Document is the document being linted.
+func (c *Context) Document() *loads.Document {
+	return c.document
+}
+
+// Spec is the spec of the document being linted.
+func (c *Context) Spec() *spec.Swagger {
+	return c.document.Spec()
+}
+
+// Analyzer is the analyzer of the document being linted.
+func (c *Context) Analyzer() *analysis.Spec {
+	return c.document.Analyzer
+}
+
+// Raw is the JSON of the document being linted.
+func (c *Context) Raw() json.RawMessage {
+	return c.document.Raw()
+}
+
+// Report records a violation of the rule at the JSON pointer of the offending node.
+func (c *Context) Report(pointer, message string) {
+	c.findings = append(c.findings, Finding{
+		Rule:     c.rule.Name(),
+		Severity: c.severity,
+		Message:  message,
+		Pointer:  pointer,
+	})
+}
+
+// Reportf records a violation of the rule, with a formatted message.
+func (c *Context) Reportf(pointer, format string, args ...any) {
+	c.Report(pointer, fmt.Sprintf(format, args...))
+}
+
+// Option configures [Lint].
+type Option func(*options)
+
+type options struct {
+	rules   []Rule
+	ruleset *Ruleset
+	source  []byte
+}
+
+// WithRules adds custom rules, checked along with the built-in ones.
+//
+// A custom rule is enabled with its own severity, unless configured otherwise by the ruleset.
+func WithRules(rules ...Rule) Option {
+	return func(o *options) {
+		o.rules = append(o.rules, rules...)
+	}
+}
+
+// WithRuleset configures the rules with a [Ruleset]. Without a ruleset, the recommended rules are checked.
+func WithRuleset(ruleset *Ruleset) Option {
+	return func(o *options) {
+		o.ruleset = ruleset
+	}
+}
+
+// WithSource sets the source of the document, JSON or YAML, to locate the findings.
+//
+// By default, findings are located in [loads.Document.SourceRaw], which is the JSON form of the document.
+// For a document loaded from YAML, WithSource with the YAML bytes is required to get positions in the
+// YAML file: the default positions locate the converted JSON instead.
+func WithSource(source []byte) Option {
+	return func(o *options) {
+		o.source = source
+	}
+}
+
+// Lint checks the document with the configured rules, and returns the findings sorted by position.
+//
+// It fails if the ruleset configures an unknown rule.
+func Lint(doc *loads.Document, opts ...Option) ([]Finding, error) {
+	var o options
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	enabled, err := o.ruleset.enabled(o.rules)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, r := range enabled {
+		ctx := &Context{document: doc, rule: r.rule, severity: r.severity}
+		r.rule.Check(ctx)
+		findings = append(findings, ctx.findings...)
+	}
+
+	source := o.source
+	if source == nil {
+		source = doc.SourceRaw()
+	}
+
+	positions := indexPositions(source)
+	for i := range findings {
+		findings[i].Position = positions.locate(findings[i].Pointer)
+	}
+
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		return cmp.Or(
+			cmp.Compare(a.Position.Line, b.Position.Line),
+			cmp.Compare(a.Position.Column, b.Position.Column),
+			strings.Compare(a.Pointer, b.Pointer),
+			strings.Compare(a.Rule, b.Rule),
+		)
+	})
+
+	return findings, nil
+}
+
+// MaxSeverity returns the highest severity of the findings, or [Off] when there are none.
+func MaxSeverity(findings []Finding) Severity {
+	highest := Off
+	for _, f := range findings {
+		highest = max(highest, f.Severity)
+	}
+
+	return highest
+}
@@ -0,0 +1,225 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/go-openapi/loads"
+)
+
+const lintFixture = `swagger: "2.0"
+info:
+  title: pets
+  version: "1.0"
+tags:
+  - name: pets
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+      description: get a pet
+      tags: [pets, animals]
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          type: string
+      responses:
+        200:
+          description: a pet
+          schema:
+            $ref: "#/definitions/Pet"
+  /pets/:
+    post:
+      operationId: getPet
+      tags: [pets]
+      responses:
+        default:
+          description: error
+definitions:
+  Pet:
+    type: object
+    properties:
+      pet_name:
+        type: string
+  unused:
+    type: string
+`
+
+func lintDocument(t *testing.T) *loads.Document {
+	t.Helper()
+
+	document, err := loads.Analyzed([]byte(lintFixture), "")
+	require.NoError(t, err)
+
+	return document
+}
+
+// summarize keeps the rule, severity and position of findings.
+func summarize(findings []Finding) []string {
+	summary := make([]string, 0, len(findings))
+	for _, f := range findings {
+		summary = append(summary, f.Position.String()+" "+f.Severity.String()+" "+f.Rule)
+	}
+
+	return summary
+}
+
+func TestLint(t *testing.T) {
+	document := lintDocument(t)
+
+	t.Run("should check the recommended rules, located in the source", func(t *testing.T) {
+		findings, err := Lint(document, WithSource([]byte(lintFixture)))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"2:1 warning info-description",
+			"9:5 error path-params",
+			"9:5 error path-params",
+			"10:7 error operation-operationId-unique",
+			"12:20 warning operation-tag-defined",
+			"23:3 warning path-no-trailing-slash",
+			"24:5 warning operation-description",
+			"27:7 warning operation-success-response",
+			"36:3 warning definition-pascal-case",
+			"36:3 warning no-unused-definitions",
+		}, summarize(findings))
+
+		assert.EqualT(t, Error, MaxSeverity(findings))
+		assert.EqualT(t, `10:7 /paths/~1pets~1{id}/get/operationId: error: operationId "getPet" is already used at /paths/~1pets~1/post/operationId [operation-operationId-unique]`,
+			findings[3].String())
+	})
+
+	t.Run("should locate findings in the JSON source by default", func(t *testing.T) {
+		findings, err := Lint(document)
+		require.NoError(t, err)
+		require.NotEmpty(t, findings)
+
+		for _, f := range findings {
+			assert.TrueT(t, f.Position.IsValid())
+			assert.EqualT(t, 1, f.Position.Line, "the JSON source holds on a single line")
+		}
+	})
+
+	t.Run("should report unresolved parameters", func(t *testing.T) {
+		dangling, err := loads.Analyzed([]byte(`{
+  "swagger": "2.0",
+  "info": {"title": "dangling", "version": "1", "description": "dangling parameters"},
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [{"$ref": "#/parameters/id"}],
+      "get": {
+        "description": "get a pet",
+        "parameters": [{"$ref": "#/parameters/missing"}],
+        "responses": {"200": {"description": "a pet"}}
+      }
+    }
+  },
+  "parameters": {
+    "id": {"name": "id", "in": "path", "required": true, "type": "string"}
+  }
+}`), "")
+		require.NoError(t, err)
+
+		var findings []Finding
+		require.NotPanics(t, func() {
+			findings, err = Lint(dangling, WithRuleset(&Ruleset{Extends: ExtendsNone, Rules: map[string]Severity{"path-params": Error}}))
+		})
+		require.NoError(t, err)
+		require.Len(t, findings, 1)
+		assert.EqualT(t, "/paths/~1pets~1{id}/get/parameters/0", findings[0].Pointer)
+		assert.StringContainsT(t, findings[0].Message, "#/parameters/missing")
+	})
+
+	t.Run("should apply a ruleset", func(t *testing.T) {
+		ruleset, err := ParseRuleset([]byte(`
+extends: none
+rules:
+  property-camel-case: error
+  operation-operationId-unique: warn
+`))
+		require.NoError(t, err)
+
+		findings, err := Lint(document, WithRuleset(ruleset), WithSource([]byte(lintFixture)))
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"10:7 warning operation-operationId-unique",
+			"34:7 error property-camel-case",
+		}, summarize(findings))
+	})
+
+	t.Run("should check custom rules", func(t *testing.T) {
+		rule := NewRule("no-post", "operations are read-only", Error, func(ctx *Context) {
+			for pth, item := range ctx.Spec().Paths.Paths {
+				if item.Post != nil {
+					ctx.Reportf(pointerOf("paths", pth, "post"), "%s should not accept POST", pth)
+				}
+			}
+		})
+
+		findings, err := Lint(document, WithRules(rule), WithRuleset(&Ruleset{Extends: ExtendsNone, Rules: map[string]Severity{"no-post": Info}}))
+		require.NoError(t, err)
+		require.Len(t, findings, 1)
+		assert.EqualT(t, "no-post", findings[0].Rule)
+		assert.EqualT(t, Info, findings[0].Severity)
+		assert.EqualT(t, "/pets/ should not accept POST", findings[0].Message)
+
+		findings, err = Lint(document, WithRules(rule), WithRuleset(&Ruleset{Rules: map[string]Severity{"no-post": Off}}))
+		require.NoError(t, err)
+		for _, f := range findings {
+			assert.NotEqual(t, "no-post", f.Rule)
+		}
+	})
+
+	t.Run("should check all the built-in rules", func(t *testing.T) {
+		findings, err := Lint(document, WithRuleset(&Ruleset{Extends: ExtendsAll}))
+		require.NoError(t, err)
+
+		rules := make(map[string]struct{})
+		for _, f := range findings {
+			rules[f.Rule] = struct{}{}
+		}
+		assert.Contains(t, rules, "info-contact")
+		assert.Contains(t, rules, "parameter-description")
+		assert.Contains(t, rules, "property-camel-case")
+		assert.Len(t, BuiltinRules(), 15)
+		assert.Len(t, RecommendedRules(), 11)
+	})
+
+	t.Run("should fail on an unknown rule", func(t *testing.T) {
+		_, err := Lint(document, WithRuleset(&Ruleset{Rules: map[string]Severity{"unknown": Error}}))
+		require.ErrorIs(t, err, ErrRuleset)
+	})
+}
+
+func TestRuleset(t *testing.T) {
+	t.Run("should load a ruleset file", func(t *testing.T) {
+		pth := filepath.Join(t.TempDir(), "ruleset.json")
+		require.NoError(t, os.WriteFile(pth, []byte(`{"extends": "all", "rules": {"info-contact": "off"}}`), 0o600))
+
+		ruleset, err := LoadRuleset(pth)
+		require.NoError(t, err)
+		assert.Equal(t, &Ruleset{Extends: ExtendsAll, Rules: map[string]Severity{"info-contact": Off}}, ruleset)
+	})
+
+	t.Run("should reject an invalid ruleset", func(t *testing.T) {
+		for _, data := range []string{
+			`extends: some`,
+			`rules: {info-contact: fatal}`,
+			`severity: error`,
+		} {
+			_, err := ParseRuleset([]byte(data))
+			require.ErrorIs(t, err, ErrRuleset, data)
+		}
+
+		_, err := LoadRuleset(filepath.Join(t.TempDir(), "missing.yaml"))
+		require.ErrorIs(t, err, ErrRuleset)
+	})
+}
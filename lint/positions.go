@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"strconv"
+	"strings"
+
+	yaml "go.yaml.in/yaml/v3"
+)
+
+// positions maps the JSON pointers of a document to their position in its source.
+type positions map[string]Position
+
+// indexPositions locates every node of a JSON or YAML source. An invalid source yields no positions.
+func indexPositions(source []byte) positions {
+	var document yaml.Node
+	if err := yaml.Unmarshal(source, &document); err != nil || len(document.Content) == 0 {
+		return nil
+	}
+
+	p := make(positions)
+	root := document.Content[0]
+	p[""] = Position{Line: root.Line, Column: root.Column}
+	p.index("", root)
+
+	return p
+}
+
+func (p positions) index(pointer string, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			child := pointer + "/" + escapeToken(key.Value)
+			p[child] = Position{Line: key.Line, Column: key.Column} // a member is located by its key
+			p.index(child, value)
+		}
+	case yaml.SequenceNode:
+		for i, value := range node.Content {
+			child := pointer + "/" + strconv.Itoa(i)
+			p[child] = Position{Line: value.Line, Column: value.Column}
+			p.index(child, value)
+		}
+	case yaml.AliasNode:
+		if node.Alias != nil {
+			p.index(pointer, node.Alias)
+		}
+	}
+}
+
+// locate returns the position of pointer, or of its closest located parent.
+func (p positions) locate(pointer string) Position {
+	for {
+		if pos, ok := p[pointer]; ok {
+			return pos
+		}
+
+		idx := strings.LastIndex(pointer, "/")
+		if idx < 0 {
+			return Position{}
+		}
+		pointer = pointer[:idx]
+	}
+}
+
+// escapeToken escapes a key to be used as a JSON pointer token.
+func escapeToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// pointerOf builds a JSON pointer from unescaped tokens.
+func pointerOf(tokens ...string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(escapeToken(token))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,308 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+var (
+	pascalCase = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+	camelCase  = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+	pathParam  = regexp.MustCompile(`\{([^}]+)\}`)
+)
+
+// builtin is a built-in rule, part of the recommended rules or not.
+type builtin struct {
+	funcRule
+
+	recommended bool
+}
+
+// BuiltinRules returns all the built-in rules.
+func BuiltinRules() []Rule {
+	rules := make([]Rule, 0, len(builtins))
+	for _, r := range builtins {
+		rules = append(rules, r)
+	}
+
+	return rules
+}
+
+// RecommendedRules returns the built-in rules checked by default: a common style guide for
+// well-documented, consistent specs.
+func RecommendedRules() []Rule {
+	var rules []Rule
+	for _, r := range builtins {
+		if r.recommended {
+			rules = append(rules, r)
+		}
+	}
+
+	return rules
+}
+
+var builtins = []builtin{
+	{recommended: true, funcRule: funcRule{
+		name: "info-description", description: "the API has a description", severity: Warning,
+		check: func(ctx *Context) {
+			if info := ctx.Spec().Info; info == nil || strings.TrimSpace(info.Description) == "" {
+				ctx.Report("/info", "the API should have a description")
+			}
+		},
+	}},
+	{funcRule: funcRule{
+		name: "info-contact", description: "the API has a contact", severity: Info,
+		check: func(ctx *Context) {
+			if info := ctx.Spec().Info; info == nil || info.Contact == nil {
+				ctx.Report("/info", "the API should have a contact")
+			}
+		},
+	}},
+	{recommended: true, funcRule: funcRule{
+		name: "operation-operationId", description: "every operation has an operationId", severity: Warning,
+		check: func(ctx *Context) {
+			forEachOperation(ctx.Spec(), func(pth, method string, op *spec.Operation) {
+				if op.ID == "" {
+					ctx.Reportf(pointerOf("paths", pth, method), "operation %s %s should have an operationId", strings.ToUpper(method), pth)
+				}
+			})
+		},
+	}},
+	{recommended: true, funcRule: funcRule{
+		name: "operation-operationId-unique", description: "operationIds are unique", severity: Error,
+		check: func(ctx *Context) {
+			seen := make(map[string]string)
+			forEachOperation(ctx.Spec(), func(pth, method string, op *spec.Operation) {
+				if op.ID == "" {
+					return
+				}
+
+				pointer := pointerOf("paths", pth, method, "operationId")
+				if first, duplicate := seen[op.ID]; duplicate {
+					ctx.Reportf(pointer, "operationId %q is already used at %s", op.ID, first)
+
+					return
+				}
+				seen[op.ID] = pointer
+			})
+		},
+	}},
+	{funcRule: funcRule{
+		name: "operation-operationId-camel-case", description: "operationIds are in camelCase", severity: Hint,
+		check: func(ctx *Context) {
+			forEachOperation(ctx.Spec(), func(pth, method string, op *spec.Operation) {
+				if op.ID != "" && !camelCase.MatchString(op.ID) {
+					ctx.Reportf(pointerOf("paths", pth, method, "operationId"), "operationId %q should be in camelCase", op.ID)
+				}
+			})
+		},
+	}},
+	{recommended: true, funcRule: funcRule{
+		name: "operation-description", description: "every operation has a description or a summary", severity: Warning,
+		check: func(ctx *Context) {
+			forEachOperation(ctx.Spec(), func(pth, method string, op *spec.Operation) {
+				if strings.TrimSpace(op.Description) == "" && strings.TrimSpace(op.Summary) == "" {
+					ctx.Reportf(pointerOf("paths", pth, method), "operation %s %s should have a description or a summary", strings.ToUpper(method), pth)
+				}
+			})
+		},
+	}},
+	{recommended: true, funcRule: funcRule{
+		name: "operation-tags", description: "every operation has a tag", severity: Warning,
+		check: func(ctx *Context) {
+			forEachOperation(ctx.Spec(), func(pth, method string, op *spec.Operation) {
+				if len(op.Tags) == 0 {
+					ctx.Reportf(pointerOf("paths", pth, method), "operation %s %s should have a tag", strings.ToUpper(method), pth)
+				}
+			})
+		},
+	}},
+	{recommended: true, funcRule: funcRule{
+		name: "operation-tag-defined", description: "the tags of operations are declared at the top level", severity: Warning,
+		check: func(ctx *Context) {
+			declared := make(map[string]struct{})
+			for _, tag := range ctx.Spec().Tags {
+				declared[tag.Name] = struct{}{}
+			}
+
+			forEachOperation(ctx.Spec(), func(pth, method string, op *spec.Operation) {
+				for i, tag := range op.Tags {
+					if _, ok := declared[tag]; !ok {
+						ctx.Reportf(pointerOf("paths", pth, method, "tags")+"/"+strconv.Itoa(i), "tag %q should be declared at the top level", tag)
+					}
+				}
+			})
+		},
+	}},
+	{recommended: true, funcRule: funcRule{
+		name: "operation-success-response", description: "every operation has a success (2xx or 3xx) response", severity: Warning,
+		check: func(ctx *Context) {
+			forEachOperation(ctx.Spec(), func(pth, method string, op *spec.Operation) {
+				if op.Responses != nil {
+					for code := range op.Responses.StatusCodeResponses {
+						if code >= 200 && code < 400 {
+							return
+						}
+					}
+				}
+				ctx.Reportf(pointerOf("paths", pth, method, "responses"), "operation %s %s should have a success response", strings.ToUpper(method), pth)
+			})
+		},
+	}},
+	{funcRule: funcRule{
+		name: "parameter-description", description: "every parameter has a description", severity: Info,
+		check: func(ctx *Context) {
+			forEachOperation(ctx.Spec(), func(pth, method string, op *spec.Operation) {
+				for i, param := range op.Parameters {
+					if param.Ref.String() == "" && strings.TrimSpace(param.Description) == "" {
+						ctx.Reportf(pointerOf("paths", pth, method, "parameters")+"/"+strconv.Itoa(i), "parameter %q should have a description", param.Name)
+					}
+				}
+			})
+		},
+	}},
+	{recommended: true, funcRule: funcRule{
+		name: "path-params", description: "path templates and path parameters match", severity: Error,
+		check: func(ctx *Context) {
+			forEachOperation(ctx.Spec(), func(pth, method string, op *spec.Operation) {
+				templated := make(map[string]struct{})
+				for _, match := range pathParam.FindAllStringSubmatch(pth, -1) {
+					templated[match[1]] = struct{}{}
+				}
+
+				pointer := pointerOf("paths", pth, method)
+				unresolved := func(param spec.Parameter, err error) bool {
+					ctx.Reportf(parameterPointer(ctx.Spec(), pth, method, op, param), "parameter of %s %s cannot be resolved: %v", strings.ToUpper(method), pth, err)
+
+					return true
+				}
+
+				declared := make(map[string]struct{})
+				for _, param := range ctx.Analyzer().SafeParamsFor(method, pth, unresolved) {
+					if param.In == "path" {
+						declared[param.Name] = struct{}{}
+					}
+				}
+
+				for _, name := range slices.Sorted(maps.Keys(templated)) {
+					if _, ok := declared[name]; !ok {
+						ctx.Reportf(pointer, "path parameter %q of %s %s is not declared", name, strings.ToUpper(method), pth)
+					}
+				}
+				for _, name := range slices.Sorted(maps.Keys(declared)) {
+					if _, ok := templated[name]; !ok {
+						ctx.Reportf(pointer, "path parameter %q of %s %s is not in the path template", name, strings.ToUpper(method), pth)
+					}
+				}
+			})
+		},
+	}},
+	{recommended: true, funcRule: funcRule{
+		name: "path-no-trailing-slash", description: "paths do not end with a slash", severity: Warning,
+		check: func(ctx *Context) {
+			for _, pth := range sortedPaths(ctx.Spec()) {
+				if len(pth) > 1 && strings.HasSuffix(pth, "/") {
+					ctx.Reportf(pointerOf("paths", pth), "path %q should not end with a slash", pth)
+				}
+			}
+		},
+	}},
+	{recommended: true, funcRule: funcRule{
+		name: "definition-pascal-case", description: "definition names are in PascalCase", severity: Warning,
+		check: func(ctx *Context) {
+			for _, name := range slices.Sorted(maps.Keys(ctx.Spec().Definitions)) {
+				if !pascalCase.MatchString(name) {
+					ctx.Reportf(pointerOf("definitions", name), "definition %q should be in PascalCase", name)
+				}
+			}
+		},
+	}},
+	{funcRule: funcRule{
+		name: "property-camel-case", description: "the properties of definitions are in camelCase", severity: Hint,
+		check: func(ctx *Context) {
+			definitions := ctx.Spec().Definitions
+			for _, name := range slices.Sorted(maps.Keys(definitions)) {
+				properties := definitions[name].Properties
+				for _, property := range slices.Sorted(maps.Keys(properties)) {
+					if !camelCase.MatchString(property) {
+						ctx.Reportf(pointerOf("definitions", name, "properties", property), "property %q of %q should be in camelCase", property, name)
+					}
+				}
+			}
+		},
+	}},
+	{recommended: true, funcRule: funcRule{
+		name: "no-unused-definitions", description: "every definition is referenced", severity: Warning,
+		check: func(ctx *Context) {
+			used := make(map[string]struct{})
+			for _, ref := range ctx.Analyzer().AllRefs() {
+				document, fragment, _ := strings.Cut(ref.String(), "#")
+				if document != "" {
+					continue
+				}
+
+				if name, ok := strings.CutPrefix(fragment, "/definitions/"); ok {
+					name, _, _ = strings.Cut(name, "/")
+					used[strings.ReplaceAll(strings.ReplaceAll(name, "~1", "/"), "~0", "~")] = struct{}{}
+				}
+			}
+
+			for _, name := range slices.Sorted(maps.Keys(ctx.Spec().Definitions)) {
+				if _, ok := used[name]; !ok {
+					ctx.Reportf(pointerOf("definitions", name), "definition %q is not used", name)
+				}
+			}
+		},
+	}},
+}
+
+// forEachOperation calls fn with every operation of the spec, in a deterministic order.
+// parameterPointer locates a parameter of an operation, declared by the operation or by its path item.
+func parameterPointer(s *spec.Swagger, pth, method string, op *spec.Operation, param spec.Parameter) string {
+	sameRef := func(p spec.Parameter) bool {
+		return p.Ref.String() == param.Ref.String()
+	}
+
+	if i := slices.IndexFunc(op.Parameters, sameRef); i >= 0 {
+		return pointerOf("paths", pth, method, "parameters") + "/" + strconv.Itoa(i)
+	}
+
+	if i := slices.IndexFunc(s.Paths.Paths[pth].Parameters, sameRef); i >= 0 {
+		return pointerOf("paths", pth, "parameters") + "/" + strconv.Itoa(i)
+	}
+
+	return pointerOf("paths", pth, method)
+}
+
+func forEachOperation(s *spec.Swagger, fn func(pth, method string, op *spec.Operation)) {
+	for _, pth := range sortedPaths(s) {
+		item := s.Paths.Paths[pth]
+		for _, entry := range []struct {
+			method string
+			op     *spec.Operation
+		}{
+			{"get", item.Get}, {"put", item.Put}, {"post", item.Post}, {"delete", item.Delete},
+			{"options", item.Options}, {"head", item.Head}, {"patch", item.Patch},
+		} {
+			if entry.op != nil {
+				fn(pth, entry.method, entry.op)
+			}
+		}
+	}
+}
+
+func sortedPaths(s *spec.Swagger) []string {
+	if s.Paths == nil {
+		return nil
+	}
+
+	return slices.Sorted(maps.Keys(s.Paths.Paths))
+}
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/go-openapi/swag/yamlutils"
+)
+
+const (
+	// ExtendsRecommended is the base ruleset of the recommended built-in rules, see [RecommendedRules].
+	ExtendsRecommended = "recommended"
+	// ExtendsAll is the base ruleset of all the built-in rules, see [BuiltinRules].
+	ExtendsAll = "all"
+	// ExtendsNone is the empty base ruleset: only the rules configured explicitly are checked.
+	ExtendsNone = "none"
+)
+
+// Ruleset configures the rules checked by [Lint] and their severity.
+type Ruleset struct {
+	// Extends is the base set of built-in rules: [ExtendsRecommended] (the default), [ExtendsAll]
+	// or [ExtendsNone]. Custom rules are part of every base set but [ExtendsNone].
+	Extends string `json:"extends,omitempty"`
+
+	// Rules sets the severity of rules, by name. A rule set to [Off] is disabled, and a rule out of
+	// the base set is enabled by any other severity.
+	Rules map[string]Severity `json:"rules,omitempty"`
+}
+
+// ParseRuleset parses a ruleset in YAML or JSON.
+func ParseRuleset(data []byte) (*Ruleset, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] != '{' {
+		yml, err := yamlutils.BytesToYAMLDoc(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrRuleset, err)
+		}
+
+		if trimmed, err = yamlutils.YAMLToJSON(yml); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrRuleset, err)
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	dec.DisallowUnknownFields()
+
+	var ruleset Ruleset
+	if err := dec.Decode(&ruleset); err != nil {
+		if errors.Is(err, ErrRuleset) {
+			return nil, err // an invalid severity
+		}
+
+		return nil, fmt.Errorf("%w: %w", ErrRuleset, err)
+	}
+
+	switch ruleset.Extends {
+	case "", ExtendsRecommended, ExtendsAll, ExtendsNone:
+	default:
+		return nil, fmt.Errorf("%w: unknown base ruleset %q", ErrRuleset, ruleset.Extends)
+	}
+
+	return &ruleset, nil
+}
+
+// LoadRuleset reads a ruleset from a YAML or JSON file.
+func LoadRuleset(path string) (*Ruleset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRuleset, err)
+	}
+
+	return ParseRuleset(data)
+}
+
+type enabledRule struct {
+	rule     Rule
+	severity Severity
+}
+
+// enabled returns the rules to check, with their configured severity, in a deterministic order.
+//
+// A custom rule with the name of a built-in rule replaces it. A nil ruleset checks the recommended rules.
+func (r *Ruleset) enabled(custom []Rule) ([]enabledRule, error) {
+	var ruleset Ruleset
+	if r != nil {
+		ruleset = *r
+	}
+
+	known := make(map[string]Rule, len(builtins)+len(custom))
+	selected := make(map[string]Severity)
+	for _, b := range builtins {
+		known[b.name] = b
+		if ruleset.Extends == ExtendsAll || (b.recommended && (ruleset.Extends == "" || ruleset.Extends == ExtendsRecommended)) {
+			selected[b.name] = b.severity
+		}
+	}
+
+	for _, c := range custom {
+		known[c.Name()] = c
+		if ruleset.Extends != ExtendsNone {
+			selected[c.Name()] = c.Severity()
+		} else {
+			delete(selected, c.Name())
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(ruleset.Rules)) {
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("%w: unknown rule %q", ErrRuleset, name)
+		}
+		selected[name] = ruleset.Rules[name]
+	}
+
+	enabled := make([]enabledRule, 0, len(selected))
+	for _, name := range slices.Sorted(maps.Keys(selected)) {
+		if severity := selected[name]; severity != Off {
+			enabled = append(enabled, enabledRule{rule: known[name], severity: severity})
+		}
+	}
+
+	return enabled, nil
+}
@@ -5,11 +5,13 @@ package loads
 
 import (
 	"encoding/json"
+	"maps"
 	"net/url"
 	"slices"
 
 	"github.com/go-openapi/spec"
 	"github.com/go-openapi/swag/loading"
+	"github.com/go-openapi/swag/yamlutils"
 )
 
 // Default chain of loaders, defined at the package level.
@@ -29,21 +31,20 @@ func init() {
 // defaultLoaders builds the built-in loader chain: a YAML matcher first, with a JSON loader as
 // the catch-all fallback.
 func defaultLoaders() *loader {
-	jsonLoader := &loader{
-		DocLoaderWithMatch: DocLoaderWithMatch{
-			Match: func(_ string) bool {
-				return true
-			},
-			Fn: JSONDoc,
-		},
-	}
+	return buildLoaderChain(DefaultLoaders()...)
+}
 
-	return jsonLoader.WithHead(&loader{
-		DocLoaderWithMatch: DocLoaderWithMatch{
-			Match: loading.YAMLMatcher,
-			Fn:    loading.YAMLDoc,
-		},
-	})
+// DefaultLoaders returns the built-in loaders, in order: a YAML loader matching YAML extensions,
+// then a catch-all JSON loader.
+//
+// This is the chain used by default at the package level. It is exposed so that it may be
+// decorated (e.g. with [IntegrityVerifier.WrapMatches]) and reinstalled with [SetLoaders] or
+// [WithDocLoaderMatches].
+func DefaultLoaders() []DocLoaderWithMatch {
+	return []DocLoaderWithMatch{
+		newFetchingDocLoader(JSONDoc, yamlToJSON, loading.YAMLMatcher),
+		newFetchingDocLoader(JSONDoc, asJSON, nil), // nil matcher: JSON catch-all fallback
+	}
 }
 
 // LoaderChain links a list of [DocLoaderWithMatch] into a single [DocLoader], preserving order.
@@ -124,6 +125,16 @@ type DocMatcher func(string) bool
 type DocLoaderWithMatch struct {
 	Fn    DocLoader
 	Match DocMatcher
+
+	// fetch and convert are only set for the built-in loaders: fetch returns the raw bytes of the
+	// document, with the same confinement as Fn, and convert turns them into what Fn returns. They
+	// let an [IntegrityVerifier] hash the document as fetched.
+	fetch   DocLoader
+	convert func([]byte) (json.RawMessage, error)
+
+	// mirrored is only set for the confined loaders: it returns the loader confined to the local
+	// mirror of a ref mapping instead, provided the mirror lies within its own confinement.
+	mirrored func(mapping RefMapping) (DocLoaderWithMatch, error)
 }
 
 // NewDocLoaderWithMatch builds a [DocLoaderWithMatch] to be used in load options.
@@ -134,10 +145,33 @@ func NewDocLoaderWithMatch(fn DocLoader, matcher DocMatcher) DocLoaderWithMatch
 	}
 }
 
+// newFetchingDocLoader builds a [DocLoaderWithMatch] that fetches documents with fetch and converts
+// them with convert.
+func newFetchingDocLoader(fetch DocLoader, convert func([]byte) (json.RawMessage, error), matcher DocMatcher) DocLoaderWithMatch {
+	return DocLoaderWithMatch{
+		Fn: func(pth string, opts ...loading.Option) (json.RawMessage, error) {
+			data, err := fetch(pth, opts...)
+			if err != nil {
+				return nil, err
+			}
+
+			return convert(data)
+		},
+		Match:   matcher,
+		fetch:   fetch,
+		convert: convert,
+	}
+}
+
 type loader struct {
 	DocLoaderWithMatch
 
 	loadingOptions []loading.Option
+	refs           refMapper
+	schemes        schemeDispatch
+	virtual        map[string]json.RawMessage // in-memory documents, by lock key (see SpecFromBytes)
+	cache          *docCache                  // documents already loaded, shared by a collection (see SpecCollection)
+	integrity      *IntegrityVerifier         // verifies every document loaded, see WithIntegrity
 
 	Next *loader
 }
@@ -159,29 +193,213 @@ func (l *loader) WithNext(next *loader) *loader {
 
 // Load the raw document from path.
 func (l *loader) Load(path string) (json.RawMessage, error) {
-	_, erp := url.Parse(path)
-	if erp != nil {
-		return nil, errLoads(erp)
+	doc, err := l.fetchDocument(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.json, nil
+}
+
+// fetch returns the raw bytes of the document at path, as fetched (e.g. the YAML source, before its
+// conversion to JSON). A document served by a custom loader, or in memory, is returned as loaded.
+func (l *loader) fetch(path string) ([]byte, error) {
+	if b, ok := l.virtualDocument(path); ok {
+		return b, nil
+	}
+
+	doc, err := l.loadDocument(path, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.raw, nil
+}
+
+// fetchDocument loads the document at path, both as fetched and converted to JSON, with a single fetch.
+// An in-memory or cached document is returned as loaded.
+func (l *loader) fetchDocument(path string) (fetchedDocument, error) {
+	if _, err := url.Parse(path); err != nil {
+		return fetchedDocument{}, errLoads(err)
+	}
+
+	if b, ok := l.virtualDocument(path); ok {
+		return fetchedDocument{raw: b, json: b}, nil
+	}
+
+	if b, ok := l.cachedDocument(path); ok {
+		return fetchedDocument{raw: b, json: b}, nil
+	}
+
+	doc, err := l.loadDocument(path, false)
+	if err != nil {
+		return fetchedDocument{}, err
+	}
+
+	if l != nil {
+		l.cache.put(path, doc.json)
+	}
+
+	return doc, nil
+}
+
+// fetchedDocument is a document as fetched, and converted to JSON.
+type fetchedDocument struct {
+	raw  []byte
+	json json.RawMessage // not set when only the raw document is fetched
+}
+
+// loadDocument loads the document at path through the chain. When raw is true, the document is
+// only fetched, not converted.
+func (l *loader) loadDocument(path string, raw bool) (fetchedDocument, error) {
+	location := path
+	prepared, err := l.prepare(path)
+	if err != nil {
+		return fetchedDocument{}, err
+	}
+
+	load := func(ldr DocLoaderWithMatch) (fetchedDocument, error) {
+		if prepared.mirror != nil && ldr.mirrored != nil {
+			// a confined loader checks the mirror against its own confinement
+			if ldr, err = ldr.mirrored(*prepared.mirror); err != nil {
+				return fetchedDocument{}, err
+			}
+		}
+
+		var data []byte
+		switch {
+		case l != nil && l.integrity != nil:
+			data, err = l.integrity.fetch(location, prepared.path, prepared.options, ldr)
+		case ldr.fetch != nil:
+			data, err = ldr.fetch(prepared.path, prepared.options...)
+		default:
+			data, err = ldr.Fn(prepared.path, prepared.options...)
+		}
+		if err != nil {
+			return fetchedDocument{}, err
+		}
+
+		switch {
+		case raw:
+			return fetchedDocument{raw: data}, nil
+		case ldr.fetch == nil:
+			return fetchedDocument{raw: data, json: data}, nil
+		}
+
+		converted, err := ldr.convert(data)
+		if err != nil {
+			return fetchedDocument{}, err
+		}
+
+		return fetchedDocument{raw: data, json: converted}, nil
+	}
+
+	if prepared.scheme != nil {
+		// a loader is registered for this URI scheme: the chain is bypassed
+		doc, err := load(DocLoaderWithMatch{Fn: prepared.scheme})
+		if err != nil {
+			return fetchedDocument{}, errLoads(err)
+		}
+
+		return doc, nil
 	}
 
 	var lastErr error = ErrNoLoader // default error if no match was found
 	for ldr := l; ldr != nil; ldr = ldr.Next {
-		if ldr.Match != nil && !ldr.Match(path) {
+		if ldr.Match != nil && !ldr.Match(prepared.path) {
 			continue
 		}
 
 		// try then move to next one if there is an error
-		b, err := ldr.Fn(path, l.loadingOptions...)
+		doc, err := load(ldr.DocLoaderWithMatch)
 		if err == nil {
-			return b, nil
+			return doc, nil
 		}
 
 		lastErr = err
 	}
 
-	return nil, errLoads(lastErr)
+	return fetchedDocument{}, errLoads(lastErr)
+}
+
+// preparedPath is a path to load, with the chain-level configuration applied (see loader.prepare).
+type preparedPath struct {
+	path    string
+	options []loading.Option // loading options to pass to the loader
+	scheme  DocLoader        // loader registered for the scheme of path, if any
+	mirror  *RefMapping      // ref mapping of path, if any
 }
 
+// prepare applies the chain-level configuration (ref mappings, offline mode, scheme dispatch) to path.
+//
+// It is nil-safe.
+func (l *loader) prepare(path string) (preparedPath, error) {
+	if l == nil {
+		return preparedPath{path: path}, nil
+	}
+
+	path, mapping, err := l.refs.resolve(path)
+	if err != nil {
+		return preparedPath{}, err
+	}
+
+	if mapping != nil {
+		// the mirror options come last, so they take precedence (loading options are last-wins)
+		prepared := preparedPath{path: path, options: slices.Clone(l.loadingOptions), mirror: mapping}
+		if mapping.FS != nil {
+			prepared.options = append(prepared.options, loading.WithFS(mapping.FS))
+
+			return prepared, nil
+		}
+
+		prepared.options = append(prepared.options, loading.WithRoot(mapping.Dir))
+
+		return prepared, nil
+	}
+
+	fn, err := l.schemes.dispatch(path)
+	if err != nil {
+		return preparedPath{}, err
+	}
+
+	return preparedPath{path: path, options: l.loadingOptions, scheme: fn}, nil
+}
+
+// virtualDocument returns the in-memory document registered at path, if any.
+func (l *loader) virtualDocument(path string) (json.RawMessage, bool) {
+	if l == nil || len(l.virtual) == 0 {
+		return nil, false
+	}
+
+	b, ok := l.virtual[lockKey(path)]
+
+	return b, ok
+}
+
+// cachedDocument returns the document already loaded from path, if the loader has a cache.
+func (l *loader) cachedDocument(path string) (json.RawMessage, bool) {
+	if l == nil {
+		return nil, false
+	}
+
+	return l.cache.get(path)
+}
+
+// loadWithOptions loads the raw document from path, with additional loading options applied after
+// the ones of the chain. It is suitable as a PathLoaderWithOptions.
+func (l *loader) loadWithOptions(path string, opts ...loading.Option) (json.RawMessage, error) {
+	if len(opts) == 0 {
+		return l.Load(path)
+	}
+
+	ldr := l.clone()
+	ldr.loadingOptions = append(ldr.loadingOptions, opts...)
+
+	return ldr.Load(path)
+}
+
+// clone copies the loader configuration. The cache, if any, is not copied: a clone may be configured
+// differently.
 func (l *loader) clone() *loader {
 	if l == nil {
 		return nil
@@ -190,6 +408,10 @@ func (l *loader) clone() *loader {
 	return &loader{
 		DocLoaderWithMatch: l.DocLoaderWithMatch,
 		loadingOptions:     slices.Clone(l.loadingOptions),
+		refs:               refMapper{mappings: slices.Clone(l.refs.mappings), offline: l.refs.offline},
+		schemes:            l.schemes.clone(),
+		virtual:            maps.Clone(l.virtual),
+		integrity:          l.integrity,
 		Next:               l.Next.clone(),
 	}
 }
@@ -206,6 +428,27 @@ func JSONDoc(path string, opts ...loading.Option) (json.RawMessage, error) {
 	return json.RawMessage(data), nil
 }
 
+// asJSON converts a JSON document fetched by [JSONDoc]: it is returned as is.
+func asJSON(data []byte) (json.RawMessage, error) {
+	return data, nil
+}
+
+// yamlToJSON converts a YAML document fetched by [JSONDoc] to JSON, like
+// [github.com/go-openapi/swag/loading.YAMLDoc] does.
+func yamlToJSON(data []byte) (json.RawMessage, error) {
+	yml, err := yamlutils.BytesToYAMLDoc(data)
+	if err != nil {
+		return nil, errLoads(err)
+	}
+
+	doc, err := yamlutils.YAMLToJSON(yml)
+	if err != nil {
+		return nil, errLoads(err)
+	}
+
+	return doc, nil
+}
+
 // AddLoader for a document, executed before other previously set loaders.
 //
 // This sets the configuration at the package level.
@@ -214,6 +457,7 @@ func JSONDoc(path string, opts ...loading.Option) (json.RawMessage, error) {
 //
 // This function updates the default loader used by [github.com/go-openapi/spec].
 // Since this sets package level globals, you shouldn't call this concurrently.
+// For a loader configuration that may be updated concurrently, use a [Registry].
 //
 // # Security
 //
@@ -250,7 +494,8 @@ func AddLoader(predicate DocMatcher, load DocLoader) {
 //
 // This sets package-level globals and the [github.com/go-openapi/spec] global loader. It is
 // not safe to call concurrently with other loads or with [AddLoader]; configure it once at
-// startup, before serving.
+// startup, before serving. For a scoped configuration that may be updated concurrently (e.g. one
+// per tenant), use a [Registry] instead.
 //
 // # Security
 //
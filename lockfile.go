@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	// LockfileName is the name of the lockfile written by [Document.Vendor].
+	LockfileName = "loads.lock.json"
+
+	lockfileVersion = 1
+)
+
+// Lockfile records the content hash of every document a spec depends on.
+//
+// It is written by [Document.Vendor] or by an [IntegrityVerifier] in update mode, and verified by
+// an [IntegrityVerifier].
+type Lockfile struct {
+	Version int `json:"version"`
+
+	// Root is the location of the root document, as loaded.
+	Root string `json:"root,omitempty"`
+
+	// Documents lists every document reachable from the root, sorted by URL.
+	Documents []LockEntry `json:"documents"`
+}
+
+// LockEntry pins a single document.
+type LockEntry struct {
+	// URL is the location of the document. Local paths are recorded as absolute, slash-separated paths.
+	URL string `json:"url"`
+
+	// Path is the location of the vendored copy, relative to the vendoring directory.
+	Path string `json:"path,omitempty"`
+
+	// SHA256 is the hex-encoded SHA-256 of the document as fetched, e.g. of its YAML source rather than
+	// its conversion to JSON.
+	SHA256 string `json:"sha256"`
+}
+
+// ReadLockfile reads a [Lockfile] from a JSON file.
+func ReadLockfile(pth string) (*Lockfile, error) {
+	data, err := os.ReadFile(pth)
+	if err != nil {
+		return nil, errLoads(err)
+	}
+
+	var lock Lockfile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, errLoads(err)
+	}
+
+	return &lock, nil
+}
+
+// WriteFile writes the lockfile as indented JSON.
+func (l *Lockfile) WriteFile(pth string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return errLoads(err)
+	}
+
+	if err := os.WriteFile(pth, append(data, '\n'), 0o600); err != nil {
+		return errLoads(err)
+	}
+
+	return nil
+}
+
+// Lookup returns the entry pinning the document at location pth.
+func (l *Lockfile) Lookup(pth string) (LockEntry, bool) {
+	if l == nil {
+		return LockEntry{}, false
+	}
+
+	key := lockKey(pth)
+	idx := slices.IndexFunc(l.Documents, func(e LockEntry) bool { return e.URL == key })
+	if idx < 0 {
+		return LockEntry{}, false
+	}
+
+	return l.Documents[idx], true
+}
+
+// set adds or replaces the entry for e.URL, keeping documents sorted.
+func (l *Lockfile) set(e LockEntry) {
+	idx := slices.IndexFunc(l.Documents, func(x LockEntry) bool { return x.URL == e.URL })
+	if idx >= 0 {
+		l.Documents[idx] = e
+
+		return
+	}
+
+	l.Documents = append(l.Documents, e)
+	slices.SortFunc(l.Documents, func(a, b LockEntry) int { return strings.Compare(a.URL, b.URL) })
+}
+
+// lockKey normalizes a document location so that the same document is always pinned under the same key.
+//
+// Remote URLs are kept as-is. Local paths are made absolute, since [github.com/go-openapi/spec]
+// resolves references to absolute paths whereas the root document is often loaded from a
+// relative path.
+func lockKey(pth string) string {
+	pth, _ = splitRef(pth)
+	if isRemote(pth) {
+		return pth
+	}
+
+	local := strings.TrimPrefix(pth, "file://")
+	if abs, err := filepath.Abs(filepath.FromSlash(local)); err == nil {
+		local = abs
+	}
+
+	return filepath.ToSlash(local)
+}
+
+func hashOf(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"strings"
+)
+
+// RefMapping maps remote documents under a URL prefix to a local mirror.
+//
+// A document whose path starts with Prefix is loaded from the mirror instead, using the remainder
+// of the path (after Prefix) as a path relative to the mirror. The mirror is either a directory
+// (Dir, read with [loading.WithRoot], so the mirror cannot be escaped) or a file system (FS).
+// When both are set, FS wins.
+type RefMapping struct {
+	Prefix string
+	Dir    string
+	FS     fs.FS
+}
+
+// refMapper rewrites paths according to a list of [RefMapping] before any loader is called.
+type refMapper struct {
+	mappings []RefMapping
+	offline  bool
+}
+
+// WithRefMapping maps every document under the URL prefix to the local directory dir.
+//
+// The mapping applies inside the document's loader, before any network access: it applies to the
+// initial load as well as to every "$ref" resolved during [Document.Expanded]. For example, with
+//
+//	loads.WithRefMapping("https://raw.githubusercontent.com/org/specs/main/", "vendor/specs")
+//
+// a reference to "https://raw.githubusercontent.com/org/specs/main/common/errors.yaml" is read from
+// "vendor/specs/common/errors.yaml".
+//
+// The prefix is matched on the parsed URL: the scheme and host must be equal, and the path prefix
+// must end at a "/" boundary, so that "https://example.com/specs" does not map
+// "https://example.com/specs-other/spec.json".
+//
+// With the confined loaders of [SetRestrictedLoaders] or [RestrictedRootsLoaders], dir must lie within
+// one of their roots.
+//
+// Mappings are tried in the order they are declared; the first matching prefix wins.
+func WithRefMapping(prefix, dir string) LoaderOption {
+	return func(opt *options) {
+		opt.refs.mappings = append(opt.refs.mappings, RefMapping{Prefix: prefix, Dir: dir})
+	}
+}
+
+// WithRefMappingFS maps every document under the URL prefix to the file system fsys,
+// e.g. an [embed.FS] holding a vendored copy of the remote documents.
+//
+// See [WithRefMapping]. With the confined loaders of [SetRestrictedLoaders] or [RestrictedRootsLoaders],
+// mapped documents are read from fsys, which confines them, rather than from the roots.
+func WithRefMappingFS(prefix string, fsys fs.FS) LoaderOption {
+	return func(opt *options) {
+		opt.refs.mappings = append(opt.refs.mappings, RefMapping{Prefix: prefix, FS: fsys})
+	}
+}
+
+// WithOffline enables a strict offline mode: any remote document that is not mapped to a local
+// mirror with [WithRefMapping] or [WithRefMappingFS] fails to load with [ErrOffline], and the
+// network is never reached.
+//
+// A remote document is one with any URI scheme other than "file" and "data", including custom
+// schemes served by [WithSchemeLoader].
+//
+// Together with the ref mappings, this allows the same spec to be loaded and expanded with or
+// without network access.
+func WithOffline() LoaderOption {
+	return func(opt *options) {
+		opt.refs.offline = true
+	}
+}
+
+// resolve rewrites path according to the first matching mapping.
+//
+// It returns the path to load and the matching mapping, if any, which directs the loader at the
+// mirror. An unmapped remote path yields [ErrOffline] in offline mode.
+func (m refMapper) resolve(path string) (string, *RefMapping, error) {
+	for i, mapping := range m.mappings {
+		if mapping.Prefix == "" {
+			continue
+		}
+
+		if rest, ok := cutURLPrefix(path, mapping.Prefix); ok {
+			return rest, &m.mappings[i], nil
+		}
+	}
+
+	if m.offline && isRemote(path) {
+		return "", nil, errLoads(fmt.Errorf("%w: %q", ErrOffline, path))
+	}
+
+	return path, nil, nil
+}
+
+// cutURLPrefix matches path against the URL prefix, and returns the remainder of its path relative
+// to the prefix.
+//
+// Both are parsed: the schemes and hosts must be equal (regardless of case), and the path of the prefix
+// must match whole segments, so that "https://host/specs" maps "https://host/specs/a.json" but not
+// "https://host/specs-other/a.json".
+func cutURLPrefix(path, prefix string) (string, bool) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return "", false
+	}
+
+	p, err := url.Parse(prefix)
+	if err != nil || p.Scheme == "" {
+		return "", false
+	}
+
+	if !strings.EqualFold(u.Scheme, p.Scheme) || !strings.EqualFold(u.Host, p.Host) {
+		return "", false
+	}
+
+	base := strings.TrimSuffix(urlPath(p), "/")
+	rest, ok := strings.CutPrefix(urlPath(u), base)
+	if !ok || (rest != "" && !strings.HasPrefix(rest, "/")) {
+		return "", false
+	}
+
+	return strings.TrimLeft(rest, "/"), true
+}
+
+// urlPath returns the path of a hierarchical URL, or the opaque part of an opaque one (e.g. "urn:a/b").
+func urlPath(u *url.URL) string {
+	if u.Opaque != "" {
+		return u.Opaque
+	}
+
+	return u.Path
+}
+
+// isRemote reports whether path designates a non-local resource, i.e. a URL with a scheme other
+// than "file" (local documents) or "data" (documents embedded in the URI).
+func isRemote(path string) bool {
+	scheme := schemeOf(path)
+
+	return scheme != schemeFile && scheme != schemeData
+}
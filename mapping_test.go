@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+const mirroredPrefix = "https://raw.example.com/org/specs/main/"
+
+func TestRefMapping(t *testing.T) {
+	t.Run("should load and expand a remote spec from a local directory mirror", func(t *testing.T) {
+		document, err := Spec(mirroredPrefix+"spec.yml",
+			WithRefMapping(mirroredPrefix, "testdata/yaml/swagger"),
+			WithOffline(),
+		)
+		require.NoError(t, err)
+		assert.EqualT(t, "api.example.com", document.Host())
+
+		// the relative "$ref" resolves against the remote URL, then is mapped to the mirror
+		expanded, err := document.Expanded()
+		require.NoError(t, err)
+		assert.JSONMarshalAsT(t, cascadeRefExpanded, expanded.Spec())
+	})
+
+	t.Run("should load and expand a remote spec from a file system mirror", func(t *testing.T) {
+		document, err := Spec(mirroredPrefix+"spec.yml",
+			WithRefMappingFS(mirroredPrefix, os.DirFS("testdata/yaml/swagger")),
+			WithOffline(),
+		)
+		require.NoError(t, err)
+
+		expanded, err := document.Expanded()
+		require.NoError(t, err)
+		assert.JSONMarshalAsT(t, cascadeRefExpanded, expanded.Spec())
+	})
+
+	t.Run("should map the initial load of JSONSpec", func(t *testing.T) {
+		document, err := JSONSpec(mirroredPrefix+"petstore-basic.json",
+			WithRefMapping(mirroredPrefix, "testdata/json"),
+			WithOffline(),
+		)
+		require.NoError(t, err)
+		assert.EqualT(t, "petstore.swagger.wordnik.com", document.Host())
+	})
+
+	t.Run("should use the first matching prefix", func(t *testing.T) {
+		document, err := Spec(mirroredPrefix+"petstore-basic.json",
+			WithRefMapping(mirroredPrefix, "testdata/json"),
+			WithRefMapping(mirroredPrefix, "testdata/yaml"),
+		)
+		require.NoError(t, err)
+		assert.EqualT(t, "petstore.swagger.wordnik.com", document.Host())
+	})
+
+	t.Run("should match the prefix at a path boundary", func(t *testing.T) {
+		prefix := "https://raw.example.com/org/specs"
+		for _, pth := range []string{
+			"https://raw.example.com/org/specs-other/spec.yml",
+			"https://raw.example.com/org/specsspec.yml",
+			"http://raw.example.com/org/specs/spec.yml",
+			"https://other.example.com/org/specs/spec.yml",
+		} {
+			_, err := Spec(pth, WithRefMapping(prefix, "testdata/yaml/swagger"), WithOffline())
+			require.ErrorIs(t, err, ErrOffline, pth)
+		}
+
+		for _, pth := range []string{
+			"https://raw.example.com/org/specs/spec.yml",
+			"HTTPS://RAW.EXAMPLE.COM/org/specs/spec.yml",
+		} {
+			document, err := Spec(pth, WithRefMapping(prefix, "testdata/yaml/swagger"), WithOffline())
+			require.NoError(t, err, pth)
+			assert.EqualT(t, "api.example.com", document.Host())
+		}
+	})
+
+	t.Run("should not escape the mirror directory", func(t *testing.T) {
+		_, err := Spec(mirroredPrefix+"../../json/petstore-basic.json",
+			WithRefMapping(mirroredPrefix, "testdata/yaml/swagger"),
+			WithOffline(),
+		)
+		require.Error(t, err)
+	})
+}
+
+func TestOffline(t *testing.T) {
+	t.Run("should fail an unmapped remote load without reaching the network", func(t *testing.T) {
+		var hits int
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			hits++
+			_, _ = rw.Write(petStoreJSON)
+		}))
+		defer srv.Close()
+
+		_, err := Spec(srv.URL, WithOffline())
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrOffline)
+		require.ErrorIs(t, err, ErrLoads)
+
+		_, err = JSONSpec(srv.URL, WithOffline())
+		require.ErrorIs(t, err, ErrOffline)
+
+		assert.EqualT(t, 0, hits)
+	})
+
+	t.Run("should fail an unmapped remote $ref during expansion", func(t *testing.T) {
+		pth := filepath.Join(t.TempDir(), "spec.json")
+		require.NoError(t, os.WriteFile(pth, []byte(`{"swagger":"2.0","info":{"title":"t","version":"1"},"paths":{},`+
+			`"definitions":{"a":{"$ref":"`+mirroredPrefix+`models.json#/definitions/a"}}}`), 0o600))
+
+		document, err := Spec(pth, WithOffline())
+		require.NoError(t, err)
+
+		_, err = document.Expanded()
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrOffline)
+	})
+
+	t.Run("should still load local documents", func(t *testing.T) {
+		document, err := Spec("testdata/yaml/swagger/spec.yml", WithOffline())
+		require.NoError(t, err)
+
+		_, err = document.Expanded()
+		require.NoError(t, err)
+	})
+}
+
+func TestIsRemote(t *testing.T) {
+	for _, pth := range []string{"https://example.com/spec.json", "http://example.com", "s3://bucket/spec.json"} {
+		assert.Truef(t, isRemote(pth), "expected %q to be remote", pth)
+	}
+
+	for _, pth := range []string{"spec.json", "/abs/spec.json", "file:///abs/spec.json", `C:\folder\spec.json`, "./rel.yaml"} {
+		assert.Falsef(t, isRemote(pth), "expected %q to be local", pth)
+	}
+}
@@ -0,0 +1,735 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-openapi/spec"
+)
+
+// MergeStrategy tells [Merge] how to resolve a conflict, i.e. a component defined differently by
+// several documents.
+type MergeStrategy int
+
+const (
+	// MergeFail makes the merge fail with [ErrMergeConflict] when there is a conflict. This is the default.
+	MergeFail MergeStrategy = iota
+
+	// MergePrefix keeps both components, and renames the one from the later document with the name
+	// of its service as a prefix, e.g. the definition "Error" of the service "Pets" becomes "PetsError",
+	// and the path "/health" becomes "/pets/health". References to the renamed component are
+	// rewritten accordingly. Merging fails with [ErrMergeConflict] when the new name is already taken.
+	MergePrefix
+
+	// MergeLastWins keeps the component from the later document.
+	MergeLastWins
+)
+
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeFail:
+		return "fail"
+	case MergePrefix:
+		return "prefix"
+	case MergeLastWins:
+		return "last-wins"
+	default:
+		return "MergeStrategy(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// MergeConflict describes a component defined differently by several documents.
+type MergeConflict struct {
+	// Section is the part of the spec holding the component: "paths", "definitions", "parameters",
+	// "responses", "securityDefinitions", "tags" or "operationId".
+	Section string
+
+	// Name is the name of the component in its section, e.g. "Error", or "/pets get" for an operation.
+	Name string
+
+	// Services are the names of the service defining the component first and of the later one.
+	Services [2]string
+
+	// Resolution is the strategy applied.
+	Resolution MergeStrategy
+
+	// Renamed is the new name of the later component, with [MergePrefix].
+	Renamed string
+}
+
+func (c MergeConflict) String() string {
+	s := fmt.Sprintf("%s %q defined by %q and %q", c.Section, c.Name, c.Services[0], c.Services[1])
+	if c.Renamed != "" {
+		return s + fmt.Sprintf(": renamed %q", c.Renamed)
+	}
+
+	return s
+}
+
+// MergeReport lists the conflicts met by [Merge].
+type MergeReport struct {
+	Conflicts []MergeConflict
+}
+
+// MergeOption configures [Merge].
+type MergeOption func(*mergeOptions)
+
+type mergeOptions struct {
+	strategy MergeStrategy
+	basePath string
+	services map[*Document]mergeService
+}
+
+type mergeService struct {
+	name     string
+	basePath string
+}
+
+// MergeOnConflict sets the strategy applied to conflicts. The default is [MergeFail].
+func MergeOnConflict(strategy MergeStrategy) MergeOption {
+	return func(o *mergeOptions) {
+		o.strategy = strategy
+	}
+}
+
+// MergeBasePath sets the base path of the merged document. The default is "/".
+//
+// The paths of every service must live under this base path.
+func MergeBasePath(basePath string) MergeOption {
+	return func(o *mergeOptions) {
+		o.basePath = basePath
+	}
+}
+
+// MergeService sets the name and the base path of the service described by doc.
+//
+// The name is the prefix of its renamed components with [MergePrefix]. It defaults to the title
+// of the document in CamelCase, e.g. "PetStore", or to "Service2" for the second document without
+// a title.
+//
+// The base path replaces the one of the document: its paths are moved under it in the merged
+// document. An empty basePath keeps the base path of the document.
+func MergeService(doc *Document, name, basePath string) MergeOption {
+	return func(o *mergeOptions) {
+		if o.services == nil {
+			o.services = make(map[*Document]mergeService)
+		}
+		o.services[doc] = mergeService{name: name, basePath: basePath}
+	}
+}
+
+// Merge combines several documents, e.g. the specs of microservices, into a single spec document.
+//
+// The paths of each document are moved under its base path (see [MergeService]), so that the merged
+// document, with the base path "/" by default (see [MergeBasePath]), serves them all. Definitions,
+// parameters, responses, security definitions and tags are combined, as well as the operations of a
+// path defined by several documents.
+//
+// A component defined identically by several documents is merged once. A component defined
+// differently is a conflict, resolved as configured with [MergeOnConflict]: all the conflicts are
+// listed by the returned report. With the default [MergeFail] strategy, Merge fails with
+// [ErrMergeConflict] after the report is complete.
+//
+// The info, host, schemes and external docs of the merged document are those of the first document.
+// Its global consumes, produces, schemes and security requirements are applied to the operations of
+// the other documents that define them differently, so that the merged operations keep their
+// settings. Relative references to other documents are resolved against each document's location.
+//
+// The documents are left untouched, and the merged document uses the loader of the first one.
+func Merge(docs []*Document, opts ...MergeOption) (*Document, *MergeReport, error) {
+	o := mergeOptions{basePath: "/"}
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	if len(docs) == 0 {
+		return nil, nil, fmt.Errorf("%w: no document to merge", ErrLoads)
+	}
+
+	m := &merger{
+		options:      o,
+		report:       &MergeReport{},
+		merged:       map[string]any{"swagger": "2.0"},
+		operationIDs: make(map[string]mergedOperation),
+		owners:       make(map[string]string),
+	}
+
+	for i, doc := range docs {
+		if err := m.add(i, doc); err != nil {
+			return nil, m.report, err
+		}
+	}
+
+	if o.strategy == MergeFail && len(m.report.Conflicts) > 0 {
+		return nil, m.report, fmt.Errorf("%w: %d conflict(s), first: %v", ErrMergeConflict, len(m.report.Conflicts), m.report.Conflicts[0])
+	}
+
+	raw, err := json.Marshal(m.merged)
+	if err != nil {
+		return nil, nil, errLoads(err)
+	}
+
+	swspec := new(spec.Swagger)
+	if err := json.Unmarshal(raw, swspec); err != nil {
+		return nil, nil, errLoads(err)
+	}
+
+	merged, err := NewDocument(swspec, docs[0].LoaderOptions()...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return merged, m.report, nil
+}
+
+// mergedSections are the sections of named components merged by name.
+var mergedSections = []string{"definitions", "parameters", "responses", "securityDefinitions"}
+
+// inheritedKeys are the global settings that operations inherit from their document.
+var inheritedKeys = []string{"consumes", "produces", "schemes", "security"}
+
+var operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+type merger struct {
+	options      mergeOptions
+	report       *MergeReport
+	merged       map[string]any
+	operationIDs map[string]mergedOperation
+	owners       map[string]string // the service defining each component of the merged document
+}
+
+type mergedOperation struct {
+	service   string
+	operation map[string]any
+}
+
+// renames maps the renamed components of a document, by section then name.
+type renames map[string]map[string]string
+
+func (r renames) set(section, name, renamed string) {
+	if r[section] == nil {
+		r[section] = make(map[string]string)
+	}
+	r[section][name] = renamed
+}
+
+func (m *merger) add(index int, doc *Document) error {
+	node, err := decodeJSON(doc.Raw())
+	if err != nil {
+		return errLoads(err)
+	}
+
+	root, ok := node.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%w: document %d is not an object", ErrLoads, index)
+	}
+
+	service := m.service(index, doc)
+	resolveExternalRefs(root, doc.specFilePath)
+
+	if index == 0 {
+		for _, key := range []string{"info", "host", "schemes", "consumes", "produces", "security", "externalDocs"} {
+			if v, ok := root[key]; ok {
+				m.merged[key] = v
+			}
+		}
+		if m.options.basePath != "/" && m.options.basePath != "" {
+			m.merged["basePath"] = m.options.basePath
+		}
+	}
+
+	names := make(renames)
+	if err := m.mergeTags(root, service, names); err != nil {
+		return err
+	}
+
+	// components are compared once their references are rewritten
+	m.renameConflicts(root, service, names)
+	m.rewrite(root, names)
+	for _, section := range mergedSections {
+		if err := m.mergeSection(root, section, service, names); err != nil {
+			return err
+		}
+	}
+	m.inherit(root)
+
+	return m.mergePaths(root, service)
+}
+
+// renameConflicts records the renames of the conflicting components of a document, with [MergePrefix].
+//
+// Renaming a component rewrites the references to it, which may in turn make other components
+// conflict: conflicts are looked for again, until no new rename is needed.
+func (m *merger) renameConflicts(root map[string]any, service mergeService, names renames) {
+	if m.options.strategy != MergePrefix {
+		return
+	}
+
+	for {
+		rewritten, _ := deepCopy(root).(map[string]any)
+		m.rewrite(rewritten, names)
+
+		var renamed bool
+		for _, section := range mergedSections {
+			components, _ := rewritten[section].(map[string]any)
+			target, _ := m.merged[section].(map[string]any)
+
+			for _, name := range slices.Sorted(maps.Keys(components)) {
+				if _, done := names[section][name]; done {
+					continue
+				}
+
+				if existing, found := target[name]; found && !reflect.DeepEqual(existing, components[name]) {
+					names.set(section, name, prefixed(service.name, name))
+					renamed = true
+				}
+			}
+		}
+
+		if !renamed {
+			return
+		}
+	}
+}
+
+// service returns the name and the effective base path of the document.
+func (m *merger) service(index int, doc *Document) mergeService {
+	service := m.options.services[doc]
+	if service.name == "" {
+		service.name = camelCase(doc.Spec().Info)
+	}
+	if service.name == "" {
+		service.name = "Service" + strconv.Itoa(index+1)
+	}
+	if service.basePath == "" {
+		service.basePath = doc.BasePath()
+	}
+	if service.basePath == "" {
+		service.basePath = "/"
+	}
+
+	return service
+}
+
+// mergeSection merges the named components of a section, e.g. definitions, renamed as recorded in names.
+func (m *merger) mergeSection(root map[string]any, section string, service mergeService, names renames) error {
+	components, _ := root[section].(map[string]any)
+	if len(components) == 0 {
+		return nil
+	}
+
+	target, _ := m.merged[section].(map[string]any)
+	if target == nil {
+		target = make(map[string]any, len(components))
+		m.merged[section] = target
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(components)) {
+		component := components[name]
+		existing, found := target[name]
+		if !found {
+			target[name] = component
+			m.claim(section, name, service)
+
+			continue
+		}
+
+		renamed, isRenamed := names[section][name]
+		if !isRenamed && reflect.DeepEqual(existing, component) {
+			continue
+		}
+
+		conflict := m.conflict(section, name, m.owner(section, name), service)
+		switch m.options.strategy {
+		case MergePrefix:
+			if err := m.checkRenamed(section, name, renamed, target[renamed], component, service); err != nil {
+				return err
+			}
+			target[renamed] = component
+			m.claim(section, renamed, service)
+			conflict.Renamed = renamed
+		case MergeLastWins:
+			target[name] = component
+			m.claim(section, name, service)
+		}
+		m.record(conflict)
+	}
+
+	return nil
+}
+
+// checkRenamed fails when the new name of a component is already used by a different one.
+func (m *merger) checkRenamed(section, name, renamed string, existing, component any, service mergeService) error {
+	if existing == nil || reflect.DeepEqual(existing, component) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: cannot rename %s %q of %q to %q, already defined by %q",
+		ErrMergeConflict, section, name, service.name, renamed, m.owner(section, renamed))
+}
+
+func (m *merger) mergeTags(root map[string]any, service mergeService, names renames) error {
+	tags, _ := root["tags"].([]any)
+	if len(tags) == 0 {
+		return nil
+	}
+
+	merged, _ := m.merged["tags"].([]any)
+	for _, t := range tags {
+		tag, ok := t.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := tag["name"].(string)
+
+		idx := slices.IndexFunc(merged, func(e any) bool {
+			other, _ := e.(map[string]any)
+
+			return other["name"] == name
+		})
+		if idx < 0 {
+			merged = append(merged, tag)
+			m.claim("tags", name, service)
+
+			continue
+		}
+
+		if reflect.DeepEqual(merged[idx], tag) {
+			continue
+		}
+
+		conflict := m.conflict("tags", name, m.owner("tags", name), service)
+		switch m.options.strategy {
+		case MergePrefix:
+			renamed := prefixed(service.name, name)
+			taken := slices.IndexFunc(merged, func(e any) bool {
+				other, _ := e.(map[string]any)
+
+				return other["name"] == renamed
+			})
+			tag["name"] = renamed
+			if taken >= 0 {
+				if err := m.checkRenamed("tags", name, renamed, merged[taken], tag, service); err != nil {
+					return err
+				}
+			} else {
+				merged = append(merged, tag)
+			}
+			names.set("tags", name, renamed)
+			m.claim("tags", renamed, service)
+			conflict.Renamed = renamed
+		case MergeLastWins:
+			merged[idx] = tag
+			m.claim("tags", name, service)
+		}
+		m.record(conflict)
+	}
+
+	m.merged["tags"] = merged
+
+	return nil
+}
+
+// resolveExternalRefs resolves the references to other documents against the location of the document.
+func resolveExternalRefs(root map[string]any, location string) {
+	walkRefs(root, func(holder map[string]any, ref string) {
+		if document, fragment := splitRef(ref); document != "" {
+			holder[refKey] = resolveDocument(location, document) + "#" + fragment
+		}
+	})
+}
+
+// rewrite applies the renames to the local references, tags and security requirements of the document.
+func (m *merger) rewrite(root map[string]any, names renames) {
+	walkRefs(root, func(holder map[string]any, ref string) {
+		document, fragment := splitRef(ref)
+		if document != "" {
+			return
+		}
+		pointer := decodeFragment(fragment)
+
+		for section, renamed := range names {
+			rest, ok := strings.CutPrefix(pointer, "/"+section+"/")
+			if !ok {
+				continue
+			}
+
+			token, tail, _ := strings.Cut(rest, "/")
+			name := unescapePointerToken(token)
+			if newName, ok := renamed[name]; ok {
+				rewritten := "/" + section + "/" + escapePointerToken(newName)
+				if tail != "" {
+					rewritten += "/" + tail
+				}
+				holder[refKey] = "#" + encodeFragment(rewritten)
+			}
+		}
+	})
+
+	renameRequirements(root["security"], names["securityDefinitions"])
+	forEachOperation(root, func(_, _ string, operation map[string]any) {
+		renameRequirements(operation["security"], names["securityDefinitions"])
+
+		if tags, ok := operation["tags"].([]any); ok {
+			for i, t := range tags {
+				if renamed, ok := names["tags"][fmt.Sprint(t)]; ok {
+					tags[i] = renamed
+				}
+			}
+		}
+	})
+}
+
+// inherit applies the global settings of the document to its operations, where they differ from
+// the ones of the merged document.
+func (m *merger) inherit(root map[string]any) {
+	forEachOperation(root, func(_, _ string, operation map[string]any) {
+		for _, key := range inheritedKeys {
+			if _, defined := operation[key]; defined || reflect.DeepEqual(root[key], m.merged[key]) {
+				continue
+			}
+
+			if value, ok := root[key]; ok {
+				operation[key] = deepCopy(value)
+			} else {
+				operation[key] = []any{}
+			}
+		}
+	})
+}
+
+func (m *merger) mergePaths(root map[string]any, service mergeService) error {
+	paths, _ := root["paths"].(map[string]any)
+
+	target, _ := m.merged["paths"].(map[string]any)
+	if target == nil {
+		target = make(map[string]any, len(paths))
+		m.merged["paths"] = target
+	}
+
+	for _, p := range slices.Sorted(maps.Keys(paths)) {
+		item, ok := paths[p].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		key, err := m.servicePath(service, p)
+		if err != nil {
+			return err
+		}
+
+		existing, found := target[key].(map[string]any)
+		if !found {
+			target[key] = item
+			if err := m.claimOperations(key, item, service); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		var conflicts []MergeConflict
+		for _, k := range slices.Sorted(maps.Keys(item)) {
+			if v, defined := existing[k]; defined && !reflect.DeepEqual(v, item[k]) {
+				conflicts = append(conflicts, m.conflict("paths", key+" "+k, m.owner("paths", key+" "+k), service))
+			}
+		}
+
+		if len(conflicts) > 0 && m.options.strategy == MergePrefix {
+			renamed := "/" + strings.ToLower(service.name) + key
+			if other, taken := target[renamed]; taken {
+				if err := m.checkRenamed("paths", key, renamed, other, item, service); err != nil {
+					return err
+				}
+			}
+			for i := range conflicts {
+				conflicts[i].Renamed = renamed
+			}
+			m.record(conflicts...)
+			target[renamed] = item
+			if err := m.claimOperations(renamed, item, service); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		m.record(conflicts...)
+		if len(conflicts) > 0 && m.options.strategy == MergeFail {
+			continue
+		}
+
+		for k, v := range item {
+			if old, ok := existing[k].(map[string]any); ok && slices.Contains(operationMethods, k) {
+				m.releaseOperationID(old)
+			}
+			existing[k] = v
+		}
+		if err := m.claimOperations(key, item, service); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// servicePath returns the path of the merged document serving the path p of the service.
+func (m *merger) servicePath(service mergeService, p string) (string, error) {
+	full := path.Join(service.basePath, p)
+	if strings.HasSuffix(p, "/") && full != "/" {
+		full += "/"
+	}
+
+	base := strings.TrimSuffix(m.options.basePath, "/")
+	switch {
+	case base == "":
+		return full, nil
+	case full == base:
+		return "/", nil
+	case strings.HasPrefix(full, base+"/"):
+		return strings.TrimPrefix(full, base), nil
+	default:
+		return "", fmt.Errorf("%w: path %q of service %q is not under the base path %q", ErrLoads, full, service.name, m.options.basePath)
+	}
+}
+
+// claimOperations registers the operations of a path item in the merged document, and resolves
+// conflicting operation IDs.
+func (m *merger) claimOperations(key string, item map[string]any, service mergeService) error {
+	for _, method := range operationMethods {
+		operation, ok := item[method].(map[string]any)
+		if !ok {
+			continue
+		}
+		m.claim("paths", key+" "+method, service)
+
+		id, _ := operation["operationId"].(string)
+		if id == "" {
+			continue
+		}
+
+		existing, found := m.operationIDs[id]
+		if !found {
+			m.operationIDs[id] = mergedOperation{service: service.name, operation: operation}
+
+			continue
+		}
+
+		conflict := m.conflict("operationId", id, existing.service, service)
+		switch m.options.strategy {
+		case MergePrefix:
+			renamed := prefixed(service.name, id)
+			if taken, found := m.operationIDs[renamed]; found {
+				return fmt.Errorf("%w: cannot rename operationId %q of %q to %q, already defined by %q",
+					ErrMergeConflict, id, service.name, renamed, taken.service)
+			}
+			operation["operationId"] = renamed
+			m.operationIDs[renamed] = mergedOperation{service: service.name, operation: operation}
+			conflict.Renamed = renamed
+		case MergeLastWins:
+			delete(existing.operation, "operationId")
+			m.operationIDs[id] = mergedOperation{service: service.name, operation: operation}
+		}
+		m.record(conflict)
+	}
+
+	return nil
+}
+
+// releaseOperationID forgets the operation ID of an operation replaced in the merged document.
+func (m *merger) releaseOperationID(operation map[string]any) {
+	id, _ := operation["operationId"].(string)
+	if existing, ok := m.operationIDs[id]; ok && reflect.ValueOf(existing.operation).Pointer() == reflect.ValueOf(operation).Pointer() {
+		delete(m.operationIDs, id)
+	}
+}
+
+func (m *merger) conflict(section, name, owner string, service mergeService) MergeConflict {
+	return MergeConflict{
+		Section:    section,
+		Name:       name,
+		Services:   [2]string{owner, service.name},
+		Resolution: m.options.strategy,
+	}
+}
+
+func (m *merger) record(conflicts ...MergeConflict) {
+	m.report.Conflicts = append(m.report.Conflicts, conflicts...)
+}
+
+// claim records that the component name of section is defined by service in the merged document.
+func (m *merger) claim(section, name string, service mergeService) {
+	m.owners[section+" "+name] = service.name
+}
+
+func (m *merger) owner(section, name string) string {
+	return m.owners[section+" "+name]
+}
+
+// forEachOperation calls fn with every operation of a generic JSON document, in a deterministic order.
+func forEachOperation(root map[string]any, fn func(path, method string, operation map[string]any)) {
+	paths, _ := root["paths"].(map[string]any)
+	for _, p := range slices.Sorted(maps.Keys(paths)) {
+		item, _ := paths[p].(map[string]any)
+		for _, method := range operationMethods {
+			if operation, ok := item[method].(map[string]any); ok {
+				fn(p, method, operation)
+			}
+		}
+	}
+}
+
+// renameRequirements renames the schemes of a list of security requirements.
+func renameRequirements(requirements any, names map[string]string) {
+	list, _ := requirements.([]any)
+	for _, r := range list {
+		requirement, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		for name, renamed := range names {
+			if scopes, ok := requirement[name]; ok {
+				delete(requirement, name)
+				requirement[renamed] = scopes
+			}
+		}
+	}
+}
+
+// prefixed prefixes name with the name of a service, e.g. "Error" with "Pets" yields "PetsError".
+func prefixed(prefix, name string) string {
+	if name == "" {
+		return prefix
+	}
+
+	return prefix + strings.ToUpper(name[:1]) + name[1:]
+}
+
+// camelCase returns the title of info in CamelCase, keeping only letters and digits.
+func camelCase(info *spec.Info) string {
+	if info == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for word := range strings.FieldsFuncSeq(info.Title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(word)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+
+	return b.String()
+}
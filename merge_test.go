@@ -0,0 +1,310 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+const (
+	mergePets = `swagger: "2.0"
+info:
+  title: pet service
+  version: "1.0"
+basePath: /pets
+produces:
+  - application/json
+securityDefinitions:
+  key:
+    type: apiKey
+    name: X-Key
+    in: header
+security:
+  - key: []
+tags:
+  - name: pets
+paths:
+  /:
+    get:
+      operationId: list
+      tags: [pets]
+      responses:
+        200:
+          description: pets
+          schema:
+            $ref: "#/definitions/Pet"
+        default:
+          description: error
+          schema:
+            $ref: "#/definitions/Error"
+definitions:
+  Pet:
+    type: object
+  Error:
+    type: object
+    properties:
+      message:
+        type: string
+`
+
+	mergeStores = `swagger: "2.0"
+info:
+  title: Store service
+  version: "1.0"
+basePath: /stores
+produces:
+  - application/xml
+securityDefinitions:
+  key:
+    type: basic
+tags:
+  - name: pets
+    description: the pets in store
+paths:
+  /:
+    get:
+      operationId: list
+      tags: [pets]
+      security:
+        - key: []
+      responses:
+        200:
+          description: stores
+          schema:
+            $ref: "#/definitions/Store"
+        default:
+          description: error
+          schema:
+            $ref: "#/definitions/Error"
+definitions:
+  Store:
+    type: object
+  Error:
+    type: object
+    properties:
+      code:
+        type: integer
+`
+)
+
+func mergeFixtures(t *testing.T) (*Document, *Document) {
+	t.Helper()
+
+	pets, err := Analyzed([]byte(mergePets), "")
+	require.NoError(t, err)
+
+	stores, err := Analyzed([]byte(mergeStores), "")
+	require.NoError(t, err)
+
+	return pets, stores
+}
+
+func TestMerge(t *testing.T) {
+	t.Run("should fail on conflicts by default, with a report", func(t *testing.T) {
+		pets, stores := mergeFixtures(t)
+
+		merged, report, err := Merge([]*Document{pets, stores})
+		require.ErrorIs(t, err, ErrMergeConflict)
+		assert.Nil(t, merged)
+		require.NotNil(t, report)
+
+		sections := make(map[string]string, len(report.Conflicts))
+		for _, conflict := range report.Conflicts {
+			sections[conflict.Section] = conflict.Name
+			assert.Equal(t, [2]string{"PetService", "StoreService"}, conflict.Services)
+			assert.EqualT(t, MergeFail, conflict.Resolution)
+		}
+		assert.Equal(t, map[string]string{
+			"definitions":         "Error",
+			"securityDefinitions": "key",
+			"tags":                "pets",
+			"operationId":         "list",
+		}, sections)
+	})
+
+	t.Run("should merge documents without conflicts", func(t *testing.T) {
+		pets, _ := mergeFixtures(t)
+		other, err := pets.Modified(func(s *spec.Swagger) error {
+			s.Info.Title = "other"
+			s.BasePath = "/others"
+			s.Paths.Paths["/"].Get.ID = "listOthers"
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		merged, report, err := Merge([]*Document{pets, other})
+		require.NoError(t, err)
+		assert.Empty(t, report.Conflicts)
+
+		assert.EqualT(t, "pet service", merged.Spec().Info.Title)
+		assert.EqualT(t, "", merged.BasePath())
+		assert.Len(t, merged.Spec().Paths.Paths, 2)
+		assert.Contains(t, merged.Spec().Paths.Paths, "/pets/")
+		assert.Contains(t, merged.Spec().Paths.Paths, "/others/")
+		assert.Len(t, merged.Spec().Definitions, 2)
+		assert.Len(t, merged.Spec().Tags, 1)
+
+		// the documents are left untouched
+		assert.Len(t, pets.Spec().Paths.Paths, 1)
+		assert.EqualT(t, "/others", other.BasePath())
+	})
+
+	t.Run("should prefix conflicting components", func(t *testing.T) {
+		pets, stores := mergeFixtures(t)
+
+		merged, report, err := Merge([]*Document{pets, stores}, MergeOnConflict(MergePrefix))
+		require.NoError(t, err)
+		assert.Len(t, report.Conflicts, 4)
+
+		swspec := merged.Spec()
+		require.Contains(t, swspec.Definitions, "Error")
+		require.Contains(t, swspec.Definitions, "StoreServiceError")
+		assert.Contains(t, swspec.Definitions["StoreServiceError"].Properties, "code")
+		assert.Contains(t, swspec.SecurityDefinitions, "StoreServiceKey")
+		assert.Len(t, swspec.Tags, 2)
+
+		stored := swspec.Paths.Paths["/stores/"].Get
+		require.NotNil(t, stored)
+		assert.EqualT(t, "StoreServiceList", stored.ID)
+		assert.Equal(t, []string{"StoreServicePets"}, stored.Tags)
+		ref := stored.Responses.Default.Schema.Ref
+		assert.EqualT(t, "#/definitions/StoreServiceError", ref.String())
+		assert.Contains(t, stored.Security[0], "StoreServiceKey")
+		assert.Equal(t, []string{"application/xml"}, stored.Produces, "the global settings should apply to the operation")
+
+		listed := swspec.Paths.Paths["/pets/"].Get
+		require.NotNil(t, listed)
+		assert.EqualT(t, "list", listed.ID)
+		assert.Empty(t, listed.Produces)
+
+		_, err = merged.Expanded()
+		require.NoError(t, err)
+	})
+
+	t.Run("should compare components once their references are rewritten", func(t *testing.T) {
+		pets, stores := mergeFixtures(t)
+		withHolder := func(s *spec.Swagger) error {
+			s.Definitions["Holder"] = *new(spec.Schema).
+				Typed("object", "").
+				SetProperty("error", *spec.RefProperty("#/definitions/Error"))
+
+			return nil
+		}
+		pets, err := pets.Modified(withHolder)
+		require.NoError(t, err)
+		stores, err = stores.Modified(withHolder)
+		require.NoError(t, err)
+
+		merged, report, err := Merge([]*Document{pets, stores}, MergeOnConflict(MergePrefix))
+		require.NoError(t, err)
+		assert.Contains(t, report.Conflicts, MergeConflict{
+			Section:    "definitions",
+			Name:       "Holder",
+			Services:   [2]string{"PetService", "StoreService"},
+			Resolution: MergePrefix,
+			Renamed:    "StoreServiceHolder",
+		})
+
+		swspec := merged.Spec()
+		ref := swspec.Definitions["Holder"].Properties["error"].Ref
+		assert.EqualT(t, "#/definitions/Error", ref.String())
+		require.Contains(t, swspec.Definitions, "StoreServiceHolder")
+		ref = swspec.Definitions["StoreServiceHolder"].Properties["error"].Ref
+		assert.EqualT(t, "#/definitions/StoreServiceError", ref.String())
+	})
+
+	t.Run("should fail when a prefixed name is already taken", func(t *testing.T) {
+		pets, stores := mergeFixtures(t)
+
+		taken, err := pets.Modified(func(s *spec.Swagger) error {
+			s.Definitions["StoreServiceError"] = *spec.StringProperty()
+
+			return nil
+		})
+		require.NoError(t, err)
+		_, _, err = Merge([]*Document{taken, stores}, MergeOnConflict(MergePrefix))
+		require.ErrorIs(t, err, ErrMergeConflict)
+		assert.StringContainsT(t, err.Error(), `"StoreServiceError"`)
+
+		taken, err = pets.Modified(func(s *spec.Swagger) error {
+			s.Paths.Paths["/other"] = spec.PathItem{PathItemProps: spec.PathItemProps{
+				Get: spec.NewOperation("StoreServiceList"),
+			}}
+
+			return nil
+		})
+		require.NoError(t, err)
+		_, _, err = Merge([]*Document{taken, stores}, MergeOnConflict(MergePrefix))
+		require.ErrorIs(t, err, ErrMergeConflict)
+		assert.StringContainsT(t, err.Error(), `operationId "list"`)
+	})
+
+	t.Run("should keep the last components", func(t *testing.T) {
+		pets, stores := mergeFixtures(t)
+
+		merged, report, err := Merge([]*Document{pets, stores}, MergeOnConflict(MergeLastWins))
+		require.NoError(t, err)
+		assert.Len(t, report.Conflicts, 4)
+
+		swspec := merged.Spec()
+		assert.Contains(t, swspec.Definitions["Error"].Properties, "code")
+		assert.EqualT(t, "basic", swspec.SecurityDefinitions["key"].Type)
+		assert.EqualT(t, "the pets in store", swspec.Tags[0].Description)
+		assert.EqualT(t, "", swspec.Paths.Paths["/pets/"].Get.ID)
+		assert.EqualT(t, "list", swspec.Paths.Paths["/stores/"].Get.ID)
+	})
+
+	t.Run("should resolve path conflicts", func(t *testing.T) {
+		pets, stores := mergeFixtures(t)
+
+		_, report, err := Merge([]*Document{pets, stores},
+			MergeService(stores, "stores", "/pets"),
+			MergeOnConflict(MergePrefix),
+		)
+		require.NoError(t, err)
+		assert.Contains(t, report.Conflicts, MergeConflict{
+			Section:    "paths",
+			Name:       "/pets/ get",
+			Services:   [2]string{"PetService", "stores"},
+			Resolution: MergePrefix,
+			Renamed:    "/stores/pets/",
+		})
+
+		merged, _, err := Merge([]*Document{pets, stores},
+			MergeService(stores, "", "/pets"),
+			MergeOnConflict(MergeLastWins),
+		)
+		require.NoError(t, err)
+		assert.Len(t, merged.Spec().Paths.Paths, 1)
+		assert.EqualT(t, "list", merged.Spec().Paths.Paths["/pets/"].Get.ID)
+	})
+
+	t.Run("should rewrite paths under the base path", func(t *testing.T) {
+		pets, stores := mergeFixtures(t)
+
+		merged, _, err := Merge([]*Document{pets, stores},
+			MergeService(pets, "", "/api/pets"),
+			MergeService(stores, "", "/api/stores"),
+			MergeBasePath("/api"),
+			MergeOnConflict(MergeLastWins),
+		)
+		require.NoError(t, err)
+		assert.EqualT(t, "/api", merged.BasePath())
+		assert.Contains(t, merged.Spec().Paths.Paths, "/pets/")
+		assert.Contains(t, merged.Spec().Paths.Paths, "/stores/")
+
+		_, _, err = Merge([]*Document{pets, stores}, MergeBasePath("/api"))
+		require.ErrorIs(t, err, ErrLoads)
+	})
+
+	t.Run("should fail without documents", func(t *testing.T) {
+		_, _, err := Merge(nil)
+		require.ErrorIs(t, err, ErrLoads)
+	})
+}
@@ -0,0 +1,25 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package mock serves a stub of the API described by a swagger (OAI v2) spec loaded with
+// [github.com/go-openapi/loads], e.g. for frontend development or tests.
+//
+// [New] builds an [http.Handler] from a [loads.Document]. Requests are routed by the paths (under the
+// base path of the spec) and methods of its operations: an unknown path yields a 404, an unknown method
+// a 405. The parameters of a request are validated against their definition (presence, type, enum and
+// validations, and the schema of a JSON body): an invalid request yields a 400, a body of a media type
+// not consumed by the operation a 415.
+//
+// A valid request gets the success response of the operation: the lowest 2xx (or 3xx) status code, or
+// the default response. A client may pick another declared response with a "Prefer: code=404" header.
+// The body of the response is the example declared for the negotiated media type, or else the example
+// of its schema, or else a value generated from its schema. Responses are deterministic.
+//
+// The handler needs no network access beyond loading the document, and may be served with
+// [net/http/httptest]:
+//
+//	handler, err := mock.New(doc)
+//	...
+//	server := httptest.NewServer(handler)
+//	defer server.Close()
+package mock
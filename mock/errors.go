@@ -0,0 +1,14 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package mock
+
+type mockError string
+
+func (e mockError) Error() string {
+	return string(e)
+}
+
+// ErrNoResponse indicates that a "Prefer: code=..." header requests a response that the operation
+// does not declare.
+const ErrNoResponse mockError = "no response declared"
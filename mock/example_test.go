@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package mock_test
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/loads/mock"
+)
+
+func ExampleNew() {
+	doc, err := loads.SpecFromBytes([]byte(`
+swagger: "2.0"
+info: {title: pets, version: "1.0"}
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, type: integer}
+      responses:
+        200:
+          description: a pet
+          schema:
+            type: object
+            properties:
+              id: {type: integer, example: 12}
+              name: {type: string}
+`), "")
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	handler, err := mock.New(doc)
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	for _, pth := range []string{"/pets/12", "/pets/twelve"} {
+		res, err := server.Client().Get(server.URL + pth)
+		if err != nil {
+			fmt.Println(err)
+
+			return
+		}
+
+		body, _ := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		fmt.Println(res.StatusCode, string(body))
+	}
+
+	// Output:
+	// 200 {"id":12,"name":"string"}
+	// 400 {"code":400,"message":"invalid request","errors":["path.id: \"twelve\" is not an integer"]}
+}
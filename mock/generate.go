@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package mock
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// sampleStrings are the values generated for the string formats.
+var sampleStrings = map[string]string{
+	"date":      "2006-01-02",
+	"date-time": "2006-01-02T15:04:05Z",
+	"uuid":      "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+	"email":     "user@example.com",
+	"hostname":  "example.com",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"byte":      "c3RyaW5n",
+	"password":  "********",
+}
+
+// generate builds a value of a schema: its example, its default, its first enum value, or else a
+// value generated from its type and validations.
+func (c *schemas) generate(s *spec.Schema) any {
+	return c.generateIn(s, nil)
+}
+
+// generateIn generates a value of a schema nested in the definitions of stack: a definition nested
+// in itself yields nil.
+func (c *schemas) generateIn(s *spec.Schema, stack []string) any {
+	if s != nil && s.Ref.String() != "" {
+		ref := s.Ref.String()
+		if slices.Contains(stack, ref) {
+			return nil
+		}
+		s, stack = c.resolve(s), append(slices.Clip(stack), ref)
+	}
+
+	switch {
+	case s == nil:
+		return nil
+	case s.Example != nil:
+		return s.Example
+	case s.Default != nil:
+		return s.Default
+	case len(s.Enum) > 0:
+		return s.Enum[0]
+	}
+
+	if len(s.AllOf) > 0 {
+		object := make(map[string]any)
+		for _, sub := range s.AllOf {
+			if value, ok := c.generateIn(&sub, stack).(map[string]any); ok {
+				maps.Copy(object, value)
+			}
+		}
+		maps.Copy(object, c.generateObject(s, stack))
+
+		return object
+	}
+
+	switch typeOf(s) {
+	case "object":
+		return c.generateObject(s, stack)
+	case "array":
+		return c.generateArray(s, stack)
+	case "string":
+		return generateString(s)
+	case "integer":
+		return int64(generateNumber(s))
+	case "number":
+		return generateNumber(s)
+	case "boolean":
+		return true
+	default:
+		return nil
+	}
+}
+
+func typeOf(s *spec.Schema) string {
+	switch {
+	case len(s.Type) > 0:
+		return s.Type[0]
+	case len(s.Properties) > 0 || s.AdditionalProperties != nil:
+		return "object"
+	case s.Items != nil:
+		return "array"
+	default:
+		return ""
+	}
+}
+
+func (c *schemas) generateObject(s *spec.Schema, stack []string) map[string]any {
+	object := make(map[string]any, len(s.Properties))
+	for _, name := range slices.Sorted(maps.Keys(s.Properties)) {
+		property := s.Properties[name]
+		object[name] = c.generateIn(&property, stack)
+	}
+
+	if len(s.Properties) == 0 && s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		object["key"] = c.generateIn(s.AdditionalProperties.Schema, stack)
+	}
+
+	return object
+}
+
+func (c *schemas) generateArray(s *spec.Schema, stack []string) []any {
+	if s.Items == nil || s.Items.Schema == nil {
+		return []any{}
+	}
+
+	item := c.generateIn(s.Items.Schema, stack)
+	if item == nil {
+		return []any{} // e.g. a definition nested in itself
+	}
+
+	n := int64(1)
+	if s.MinItems != nil {
+		n = max(n, *s.MinItems)
+	}
+	if s.MaxItems != nil {
+		n = min(n, *s.MaxItems)
+	}
+
+	items := make([]any, 0, n)
+	for range n {
+		items = append(items, item)
+	}
+
+	return items
+}
+
+func generateString(s *spec.Schema) string {
+	value, ok := sampleStrings[s.Format]
+	if !ok {
+		value = "string"
+	}
+
+	if s.MinLength != nil && int64(len(value)) < *s.MinLength {
+		value += strings.Repeat("x", int(*s.MinLength)-len(value))
+	}
+	if s.MaxLength != nil && int64(len(value)) > *s.MaxLength {
+		value = value[:*s.MaxLength]
+	}
+
+	return value
+}
+
+// generateNumber returns 0, or the closest value allowed by the validations.
+func generateNumber(s *spec.Schema) float64 {
+	value := 0.0
+	if s.Minimum != nil {
+		value = *s.Minimum
+		if s.ExclusiveMinimum {
+			value++
+		}
+	} else if s.Maximum != nil && *s.Maximum < 0 {
+		value = *s.Maximum
+		if s.ExclusiveMaximum {
+			value--
+		}
+	}
+
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		if rem := value / *s.MultipleOf; rem != float64(int64(rem)) {
+			value = float64(int64(rem)+1) * *s.MultipleOf
+		}
+	}
+
+	return value
+}
+
+// formatValue writes a generated value as a header value.
+func formatValue(value any, collectionFormat string) string {
+	items, ok := value.([]any)
+	if !ok {
+		if value == nil {
+			return ""
+		}
+
+		return fmt.Sprint(value)
+	}
+
+	separator := ","
+	switch collectionFormat {
+	case "ssv":
+		separator = " "
+	case "tsv":
+		separator = "\t"
+	case "pipes":
+		separator = "|"
+	}
+
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		parts = append(parts, fmt.Sprint(item))
+	}
+
+	return strings.Join(parts, separator)
+}
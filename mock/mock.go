@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+
+	"github.com/go-openapi/loads"
+)
+
+const defaultMediaType = "application/json"
+
+// Option configures [New].
+type Option func(*options)
+
+type options struct {
+	validation bool
+}
+
+// WithoutValidation disables the validation of requests: any request routed to an operation gets
+// its response.
+func WithoutValidation() Option {
+	return func(o *options) {
+		o.validation = false
+	}
+}
+
+// Handler serves a stub of the API of a document. It is safe for concurrent use.
+type Handler struct {
+	options
+
+	basePath string
+	routes   []*route
+}
+
+// New builds a [Handler] serving the operations of the document.
+//
+// The document is expanded, through its loader, to resolve the definitions of parameters and
+// responses: New fails if it cannot be expanded.
+func New(doc *loads.Document, opts ...Option) (*Handler, error) {
+	o := options{validation: true}
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	expanded, err := doc.Expanded()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Handler{
+		options:  o,
+		basePath: strings.TrimSuffix(expanded.BasePath(), "/"),
+		routes:   routesOf(expanded.Analyzer, &schemas{root: expanded.Spec()}),
+	}, nil
+}
+
+// ServeHTTP routes the request to an operation, validates it and writes the response of the operation.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pth, ok := strings.CutPrefix(r.URL.EscapedPath(), h.basePath) // path parameters are unescaped once matched
+	if !ok || (pth != "" && !strings.HasPrefix(pth, "/")) {
+		writeError(w, http.StatusNotFound, "path "+r.URL.Path+" not found", nil)
+
+		return
+	}
+	if pth == "" {
+		pth = "/"
+	}
+
+	rt, pathValues := h.match(pth)
+	if rt == nil {
+		writeError(w, http.StatusNotFound, "path "+r.URL.Path+" not found", nil)
+
+		return
+	}
+
+	op, ok := rt.operations[r.Method]
+	if !ok {
+		w.Header().Set("Allow", strings.Join(slices.Sorted(maps.Keys(rt.operations)), ", "))
+		writeError(w, http.StatusMethodNotAllowed, "method "+r.Method+" not allowed on "+r.URL.Path, nil)
+
+		return
+	}
+
+	if h.validation {
+		if status, errs := op.validate(r, pathValues); len(errs) > 0 {
+			writeError(w, status, "invalid request", errs)
+
+			return
+		}
+	}
+
+	op.respond(w, r)
+}
+
+// respond writes the response selected by the request.
+func (o *operation) respond(w http.ResponseWriter, r *http.Request) {
+	status, response, err := o.response(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+
+		return
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(response.Headers)) {
+		header := response.Headers[name]
+		w.Header().Set(name, formatValue(o.schemas.generate(simpleSchema(header.SimpleSchema, header.CommonValidations)), header.CollectionFormat))
+	}
+
+	mediaType := negotiate(r.Header.Get("Accept"), o.produces)
+	body, ok := response.Examples[mediaType]
+	if !ok {
+		if response.Schema == nil {
+			w.WriteHeader(status)
+
+			return
+		}
+		body = o.schemas.generate(response.Schema)
+	}
+
+	var data []byte
+	if s, isString := body.(string); isString && !isJSON(mediaType) {
+		data = []byte(s)
+	} else if data, err = json.Marshal(body); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error(), nil)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+// response selects the response of the operation: the one picked by a "Prefer: code=..." header,
+// or the success response.
+func (o *operation) response(r *http.Request) (int, spec.Response, error) {
+	var responses spec.Responses
+	if o.op.Responses != nil {
+		responses = *o.op.Responses
+	}
+
+	if code, ok := preferredCode(r.Header.Values("Prefer")); ok {
+		if response, declared := responses.StatusCodeResponses[code]; declared {
+			return code, response, nil
+		}
+		if responses.Default != nil {
+			return code, *responses.Default, nil
+		}
+
+		return 0, spec.Response{}, fmt.Errorf("%w for status code %d", ErrNoResponse, code)
+	}
+
+	codes := slices.Sorted(maps.Keys(responses.StatusCodeResponses))
+	for _, code := range codes {
+		if code >= http.StatusOK && code < http.StatusBadRequest {
+			return code, responses.StatusCodeResponses[code], nil
+		}
+	}
+
+	if responses.Default != nil {
+		return http.StatusOK, *responses.Default, nil
+	}
+
+	if len(codes) > 0 {
+		return codes[0], responses.StatusCodeResponses[codes[0]], nil
+	}
+
+	return http.StatusOK, spec.Response{}, nil
+}
+
+// preferredCode reads the status code requested by a "Prefer: code=..." header.
+func preferredCode(values []string) (int, bool) {
+	for _, value := range values {
+		for preference := range strings.SplitSeq(value, ",") {
+			name, code, ok := strings.Cut(strings.TrimSpace(preference), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(name), "code") {
+				continue
+			}
+
+			if n, err := strconv.Atoi(strings.Trim(strings.TrimSpace(code), `"`)); err == nil {
+				return n, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// negotiate picks the first media type produced by the operation that the Accept header accepts,
+// or else the first one produced.
+func negotiate(accept string, produces []string) string {
+	if len(produces) == 0 {
+		return defaultMediaType
+	}
+
+	for accepted := range strings.SplitSeq(accept, ",") {
+		accepted, _, _ = strings.Cut(accepted, ";")
+		accepted = strings.TrimSpace(accepted)
+		for _, produced := range produces {
+			if matchMediaType(accepted, produced) {
+				return produced
+			}
+		}
+	}
+
+	return produces[0]
+}
+
+// matchMediaType tells if the media type pattern, e.g. "application/*", matches the media type.
+func matchMediaType(pattern, mediaType string) bool {
+	base, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		base = mediaType
+	}
+
+	switch {
+	case pattern == "*/*":
+		return true
+	case strings.HasSuffix(pattern, "/*"):
+		return strings.HasPrefix(base, strings.TrimSuffix(pattern, "*"))
+	default:
+		return strings.EqualFold(pattern, base)
+	}
+}
+
+func isJSON(mediaType string) bool {
+	base, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		base = mediaType
+	}
+
+	return base == "application/json" || strings.HasSuffix(base, "+json")
+}
+
+// writeError writes an error response in JSON.
+func writeError(w http.ResponseWriter, status int, message string, errs []string) {
+	data, _ := json.Marshal(struct { //nolint:errchkjson // marshaling strings cannot fail
+		Code    int      `json:"code"`
+		Message string   `json:"message"`
+		Errors  []string `json:"errors,omitempty"`
+	}{Code: status, Message: message, Errors: errs})
+
+	w.Header().Set("Content-Type", defaultMediaType)
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
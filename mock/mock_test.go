@@ -0,0 +1,281 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package mock
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/go-openapi/loads"
+)
+
+const petstore = `swagger: "2.0"
+info:
+  title: pets
+  version: "1.0"
+basePath: /api
+consumes: [application/json]
+produces: [application/json]
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          type: integer
+          maximum: 100
+        - name: tags
+          in: query
+          type: array
+          items:
+            type: string
+            enum: [cat, dog]
+      responses:
+        200:
+          description: pets
+          schema:
+            type: array
+            items:
+              $ref: "#/definitions/Pet"
+    post:
+      parameters:
+        - name: pet
+          in: body
+          required: true
+          schema:
+            $ref: "#/definitions/Pet"
+      responses:
+        201:
+          description: created
+          examples:
+            application/json: {id: 7, name: Rex}
+  /pets/mine:
+    get:
+      produces: [text/plain]
+      responses:
+        200:
+          description: my pet
+          schema:
+            type: string
+            example: Felix
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        type: integer
+    get:
+      parameters:
+        - name: X-Request-Id
+          in: header
+          type: string
+          format: uuid
+      responses:
+        200:
+          description: a pet
+          headers:
+            X-Rate-Limit:
+              type: integer
+              minimum: 10
+          schema:
+            $ref: "#/definitions/Pet"
+        404:
+          description: not found
+          schema:
+            $ref: "#/definitions/Error"
+    delete:
+      responses:
+        204:
+          description: deleted
+  /login:
+    post:
+      consumes: [application/x-www-form-urlencoded]
+      parameters:
+        - name: user
+          in: formData
+          required: true
+          type: string
+          pattern: "^[a-z]+$"
+      responses:
+        204:
+          description: logged in
+definitions:
+  Pet:
+    type: object
+    required: [name]
+    properties:
+      id:
+        type: integer
+        format: int64
+      name:
+        type: string
+        minLength: 1
+      kind:
+        type: string
+        enum: [cat, dog]
+      born:
+        type: string
+        format: date
+      friends:
+        type: array
+        items:
+          $ref: "#/definitions/Pet"
+  Error:
+    type: object
+    properties:
+      code:
+        type: integer
+        minimum: 400
+      message:
+        type: string
+`
+
+func petstoreHandler(t *testing.T, opts ...Option) *Handler {
+	t.Helper()
+
+	document, err := loads.SpecFromBytes([]byte(petstore), "")
+	require.NoError(t, err)
+
+	handler, err := New(document, opts...)
+	require.NoError(t, err)
+
+	return handler
+}
+
+// serve records the response of the handler to a request.
+func serve(handler http.Handler, method, target, body string, header ...string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+
+	r := httptest.NewRequest(method, target, reader)
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestHandler(t *testing.T) {
+	handler := petstoreHandler(t)
+
+	t.Run("should serve generated responses", func(t *testing.T) {
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		res, err := server.Client().Get(server.URL + "/api/pets/12")
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		assert.EqualT(t, http.StatusOK, res.StatusCode)
+		assert.EqualT(t, "application/json", res.Header.Get("Content-Type"))
+		assert.EqualT(t, "10", res.Header.Get("X-Rate-Limit"))
+		assert.JSONEqT(t, `{
+  "id": 0,
+  "name": "string",
+  "kind": "cat",
+  "born": "2006-01-02",
+  "friends": [{"id": 0, "name": "string", "kind": "cat", "born": "2006-01-02", "friends": []}]
+}`, string(body))
+	})
+
+	t.Run("should serve declared examples", func(t *testing.T) {
+		w := serve(handler, http.MethodPost, "/api/pets", `{"name": "Rex"}`)
+		assert.EqualT(t, http.StatusCreated, w.Code)
+		assert.JSONEqT(t, `{"id": 7, "name": "Rex"}`, w.Body.String())
+
+		w = serve(handler, http.MethodGet, "/api/pets/mine", "")
+		assert.EqualT(t, http.StatusOK, w.Code)
+		assert.EqualT(t, "text/plain", w.Header().Get("Content-Type"))
+		assert.EqualT(t, "Felix", w.Body.String())
+
+		w = serve(handler, http.MethodDelete, "/api/pets/12", "")
+		assert.EqualT(t, http.StatusNoContent, w.Code)
+		assert.EqualT(t, "", w.Body.String())
+	})
+
+	t.Run("should serve the preferred response", func(t *testing.T) {
+		w := serve(handler, http.MethodGet, "/api/pets/12", "", "Prefer", "code=404")
+		assert.EqualT(t, http.StatusNotFound, w.Code)
+		assert.JSONEqT(t, `{"code": 400, "message": "string"}`, w.Body.String())
+
+		w = serve(handler, http.MethodGet, "/api/pets/12", "", "Prefer", "code=500")
+		assert.EqualT(t, http.StatusBadRequest, w.Code)
+		assert.StringContainsT(t, w.Body.String(), ErrNoResponse.Error())
+	})
+
+	t.Run("should route by path and method", func(t *testing.T) {
+		w := serve(handler, http.MethodGet, "/api/unknown", "")
+		assert.EqualT(t, http.StatusNotFound, w.Code)
+
+		w = serve(handler, http.MethodGet, "/pets", "")
+		assert.EqualT(t, http.StatusNotFound, w.Code)
+
+		w = serve(handler, http.MethodPut, "/api/pets", "")
+		assert.EqualT(t, http.StatusMethodNotAllowed, w.Code)
+		assert.EqualT(t, "GET, POST", w.Header().Get("Allow"))
+	})
+
+	t.Run("should validate parameters", func(t *testing.T) {
+		for _, tc := range []struct {
+			method, target, body string
+			header               []string
+			status               int
+			errors               string
+		}{
+			{method: http.MethodGet, target: "/api/pets?limit=10&tags=cat,dog", status: http.StatusOK},
+			{method: http.MethodGet, target: "/api/pets?limit=ten", status: http.StatusBadRequest, errors: `["query.limit: \"ten\" is not an integer"]`},
+			{method: http.MethodGet, target: "/api/pets?limit=101&tags=cat,cow", status: http.StatusBadRequest, errors: `[
+  "query.limit: must be less than or equal to 100",
+  "query.tags.1: must be one of \"cat\", \"dog\""
+]`},
+			{method: http.MethodGet, target: "/api/pets/twelve", status: http.StatusBadRequest, errors: `["path.id: \"twelve\" is not an integer"]`},
+			{method: http.MethodPost, target: "/api/pets", status: http.StatusBadRequest, errors: `["body.pet: is required"]`},
+			{method: http.MethodPost, target: "/api/pets", body: `{"name": "", "kind": "cow", "friends": [{}]}`, status: http.StatusBadRequest, errors: `[
+  "body.pet.friends.0.name: is required",
+  "body.pet.kind: must be one of \"cat\", \"dog\"",
+  "body.pet.name: must be at least 1 characters long"
+]`},
+			{method: http.MethodPost, target: "/api/pets", body: `{"name": `, status: http.StatusBadRequest},
+			{
+				method: http.MethodPost, target: "/api/login", body: "user=rex", header: []string{"Content-Type", "application/x-www-form-urlencoded"},
+				status: http.StatusNoContent,
+			},
+			{
+				method: http.MethodPost, target: "/api/login", body: "user=Rex", header: []string{"Content-Type", "application/x-www-form-urlencoded"},
+				status: http.StatusBadRequest, errors: `["formData.user: must match \"^[a-z]+$\""]`,
+			},
+			{
+				method: http.MethodPost, target: "/api/pets", body: `<pet/>`, header: []string{"Content-Type", "application/xml"},
+				status: http.StatusUnsupportedMediaType,
+			},
+		} {
+			w := serve(handler, tc.method, tc.target, tc.body, tc.header...)
+			assert.EqualT(t, tc.status, w.Code, tc.target)
+
+			if tc.errors != "" {
+				assert.JSONEqT(t, `{"code": 400, "message": "invalid request", "errors": `+tc.errors+`}`, w.Body.String(), tc.target)
+			}
+		}
+	})
+
+	t.Run("should optionally skip validation", func(t *testing.T) {
+		w := serve(petstoreHandler(t, WithoutValidation()), http.MethodGet, "/api/pets?limit=ten", "")
+		assert.EqualT(t, http.StatusOK, w.Code)
+		assert.StringContainsT(t, w.Body.String(), `"name":"string"`)
+	})
+}
@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package mock
+
+import (
+	"cmp"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/analysis"
+	"github.com/go-openapi/spec"
+)
+
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// route matches a path template of the spec, e.g. "/pets/{id}".
+type route struct {
+	template   string
+	pattern    *regexp.Regexp
+	names      []string // the names of the path parameters, in the order of the template
+	literal    int      // the length of the literal parts of the template
+	operations map[string]*operation
+}
+
+// operation is an operation of the spec, with its resolved parameters and media types.
+type operation struct {
+	op       *spec.Operation
+	params   []spec.Parameter
+	consumes []string
+	produces []string
+	schemas  *schemas
+}
+
+// schemas resolves the references left in an expanded spec, to definitions nested in themselves.
+type schemas struct {
+	root *spec.Swagger
+}
+
+// resolve follows the reference of a schema, if any. An unresolved reference yields nil.
+func (c *schemas) resolve(s *spec.Schema) *spec.Schema {
+	for s != nil && s.Ref.String() != "" {
+		resolved, err := spec.ResolveRef(c.root, &s.Ref)
+		if err != nil || resolved == s {
+			return nil
+		}
+		s = resolved
+	}
+
+	return s
+}
+
+// routesOf builds the routes of the operations of an expanded spec, the most specific first: a
+// template with fewer parameters, or else with longer literal parts, is tried first.
+func routesOf(an *analysis.Spec, c *schemas) []*route {
+	byTemplate := make(map[string]*route)
+	for method, operations := range an.Operations() {
+		for template, op := range operations {
+			rt, ok := byTemplate[template]
+			if !ok {
+				rt = newRoute(template)
+				byTemplate[template] = rt
+			}
+
+			params := an.ParamsFor(method, template)
+			rt.operations[method] = &operation{
+				op:       op,
+				params:   slices.SortedFunc(maps.Values(params), compareParams),
+				consumes: an.ConsumesFor(op),
+				produces: an.ProducesFor(op),
+				schemas:  c,
+			}
+		}
+	}
+
+	return slices.SortedFunc(maps.Values(byTemplate), func(a, b *route) int {
+		return cmp.Or(
+			cmp.Compare(len(a.names), len(b.names)),
+			cmp.Compare(b.literal, a.literal),
+			strings.Compare(a.template, b.template),
+		)
+	})
+}
+
+func newRoute(template string) *route {
+	rt := &route{template: template, operations: make(map[string]*operation)}
+
+	var expr strings.Builder
+	expr.WriteByte('^')
+	last := 0
+	for _, match := range pathParam.FindAllStringSubmatchIndex(template, -1) {
+		literal := template[last:match[0]]
+		rt.literal += len(literal)
+		expr.WriteString(regexp.QuoteMeta(literal))
+		expr.WriteString("([^/]+)")
+		rt.names = append(rt.names, template[match[2]:match[3]])
+		last = match[1]
+	}
+	rt.literal += len(template) - last
+	expr.WriteString(regexp.QuoteMeta(template[last:]))
+	expr.WriteByte('$')
+	rt.pattern = regexp.MustCompile(expr.String())
+
+	return rt
+}
+
+// match finds the route of a path, relative to the base path, and returns the values of its path parameters.
+func (h *Handler) match(pth string) (*route, map[string]string) {
+	for _, rt := range h.routes {
+		submatches := rt.pattern.FindStringSubmatch(pth)
+		if submatches == nil {
+			continue
+		}
+
+		values := make(map[string]string, len(rt.names))
+		for i, name := range rt.names {
+			value, err := url.PathUnescape(submatches[i+1])
+			if err != nil {
+				value = submatches[i+1]
+			}
+			values[name] = value
+		}
+
+		return rt, values
+	}
+
+	return nil, nil
+}
+
+func compareParams(a, b spec.Parameter) int {
+	return cmp.Or(strings.Compare(a.In, b.In), strings.Compare(a.Name, b.Name))
+}
@@ -0,0 +1,443 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"mime"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	maxBodySize   = 10 << 20
+	maxMemorySize = 32 << 20
+)
+
+// validate checks the request against the parameters of the operation, and returns the status code
+// and the messages of the violations.
+func (o *operation) validate(r *http.Request, pathValues map[string]string) (int, []string) {
+	if status, msg := o.checkContentType(r); msg != "" {
+		return status, []string{msg}
+	}
+
+	var errs []string
+	for _, param := range o.params {
+		where := param.In + "." + param.Name
+		if param.In == "body" {
+			errs = append(errs, o.schemas.validateBody(r, param, where)...)
+
+			continue
+		}
+
+		values, present := o.values(r, param, pathValues)
+		empty := present && len(values) == 1 && values[0] == ""
+		if !present || (empty && !param.AllowEmptyValue) {
+			if param.Required {
+				errs = append(errs, where+": is required")
+			}
+
+			continue
+		}
+
+		if empty || param.Type == "file" {
+			continue
+		}
+
+		value, err := parseValue(values, param.SimpleSchema)
+		if err != "" {
+			errs = append(errs, where+": "+err)
+
+			continue
+		}
+
+		errs = append(errs, o.schemas.validateValue(value, simpleSchema(param.SimpleSchema, param.CommonValidations), where)...)
+	}
+
+	return http.StatusBadRequest, errs
+}
+
+// checkContentType checks that the body of the request, if any, is of a media type consumed by the operation.
+func (o *operation) checkContentType(r *http.Request) (int, string) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" || len(o.consumes) == 0 || !o.hasBody() {
+		return 0, ""
+	}
+
+	for _, consumed := range o.consumes {
+		if matchMediaType(consumed, contentType) || matchMediaType(mediaTypeOf(contentType), consumed) {
+			return 0, ""
+		}
+	}
+
+	return http.StatusUnsupportedMediaType, fmt.Sprintf("unsupported media type %q, expected one of %s", contentType, strings.Join(o.consumes, ", "))
+}
+
+func (o *operation) hasBody() bool {
+	return slices.ContainsFunc(o.params, func(p spec.Parameter) bool {
+		return p.In == "body" || p.In == "formData"
+	})
+}
+
+// values returns the raw values of a non-body parameter, and whether it is present in the request.
+func (o *operation) values(r *http.Request, param spec.Parameter, pathValues map[string]string) ([]string, bool) {
+	switch param.In {
+	case "path":
+		value, ok := pathValues[param.Name]
+
+		return []string{value}, ok
+	case "query":
+		values, ok := r.URL.Query()[param.Name]
+
+		return values, ok
+	case "header":
+		values := r.Header.Values(param.Name)
+
+		return values, len(values) > 0
+	case "formData":
+		if strings.HasPrefix(mediaTypeOf(r.Header.Get("Content-Type")), "multipart/") {
+			if err := r.ParseMultipartForm(maxMemorySize); err != nil {
+				return nil, false
+			}
+			if _, ok := r.MultipartForm.File[param.Name]; ok {
+				return []string{param.Name}, true
+			}
+		} else if err := r.ParseForm(); err != nil {
+			return nil, false
+		}
+		values, ok := r.PostForm[param.Name]
+
+		return values, ok
+	default:
+		return nil, false
+	}
+}
+
+// parseValue parses the raw values of a parameter as its type.
+func parseValue(values []string, s spec.SimpleSchema) (any, string) {
+	if s.Type != "array" {
+		return parseScalar(values[0], s.Type)
+	}
+
+	if s.CollectionFormat != "multi" {
+		values = splitCollection(values[0], s.CollectionFormat)
+	}
+
+	items := make([]any, 0, len(values))
+	for _, raw := range values {
+		var (
+			item any
+			err  string
+		)
+		if s.Items != nil {
+			item, err = parseValue([]string{raw}, s.Items.SimpleSchema)
+		} else {
+			item = raw
+		}
+		if err != "" {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, ""
+}
+
+func splitCollection(value, format string) []string {
+	if value == "" {
+		return nil
+	}
+
+	switch format {
+	case "ssv":
+		return strings.Split(value, " ")
+	case "tsv":
+		return strings.Split(value, "\t")
+	case "pipes":
+		return strings.Split(value, "|")
+	default:
+		return strings.Split(value, ",")
+	}
+}
+
+func parseScalar(value, tpe string) (any, string) {
+	switch tpe {
+	case "integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Sprintf("%q is not an integer", value)
+		}
+
+		return float64(n), ""
+	case "number":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Sprintf("%q is not a number", value)
+		}
+
+		return n, ""
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Sprintf("%q is not a boolean", value)
+		}
+
+		return b, ""
+	default:
+		return value, ""
+	}
+}
+
+// validateBody checks the JSON body of the request against the schema of the body parameter.
+func (c *schemas) validateBody(r *http.Request, param spec.Parameter, where string) []string {
+	if r.Body == nil {
+		return requiredBody(param, where)
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxBodySize))
+	if err != nil {
+		return []string{where + ": " + err.Error()}
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return requiredBody(param, where)
+	}
+
+	if contentType := r.Header.Get("Content-Type"); contentType != "" && !isJSON(contentType) {
+		return nil // only JSON bodies are validated
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return []string{where + ": invalid JSON: " + err.Error()}
+	}
+
+	return c.validateValue(value, param.Schema, where)
+}
+
+func requiredBody(param spec.Parameter, where string) []string {
+	if param.Required {
+		return []string{where + ": is required"}
+	}
+
+	return nil
+}
+
+// validateValue checks a JSON value against a schema, and returns the messages of the violations.
+//
+// Formats are not checked.
+func (c *schemas) validateValue(value any, s *spec.Schema, where string) []string {
+	if s = c.resolve(s); s == nil {
+		return nil
+	}
+
+	if value == nil {
+		if s.Nullable || isNullable(s) || len(s.Type) == 0 {
+			return nil
+		}
+
+		return []string{where + ": must be of type " + strings.Join(s.Type, " or ")}
+	}
+
+	if len(s.Type) > 0 && !slices.ContainsFunc(s.Type, func(tpe string) bool { return hasType(value, tpe) }) {
+		return []string{where + ": must be of type " + strings.Join(s.Type, " or ")}
+	}
+
+	var errs []string
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return reflect.DeepEqual(e, value) }) {
+		errs = append(errs, fmt.Sprintf("%s: must be one of %s", where, formatEnum(s.Enum)))
+	}
+
+	for _, sub := range s.AllOf {
+		errs = append(errs, c.validateValue(value, &sub, where)...)
+	}
+
+	switch v := value.(type) {
+	case float64:
+		errs = append(errs, validateNumber(v, s, where)...)
+	case string:
+		errs = append(errs, validateString(v, s, where)...)
+	case []any:
+		errs = append(errs, c.validateArray(v, s, where)...)
+	case map[string]any:
+		errs = append(errs, c.validateObject(v, s, where)...)
+	}
+
+	return errs
+}
+
+func validateNumber(v float64, s *spec.Schema, where string) []string {
+	var errs []string
+	if s.Maximum != nil && (v > *s.Maximum || (s.ExclusiveMaximum && v == *s.Maximum)) {
+		errs = append(errs, fmt.Sprintf("%s: must be less than%s %v", where, orEqual(s.ExclusiveMaximum), *s.Maximum))
+	}
+	if s.Minimum != nil && (v < *s.Minimum || (s.ExclusiveMinimum && v == *s.Minimum)) {
+		errs = append(errs, fmt.Sprintf("%s: must be greater than%s %v", where, orEqual(s.ExclusiveMinimum), *s.Minimum))
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		if q := v / *s.MultipleOf; q != math.Trunc(q) {
+			errs = append(errs, fmt.Sprintf("%s: must be a multiple of %v", where, *s.MultipleOf))
+		}
+	}
+
+	return errs
+}
+
+func orEqual(exclusive bool) string {
+	if exclusive {
+		return ""
+	}
+
+	return " or equal to"
+}
+
+func validateString(v string, s *spec.Schema, where string) []string {
+	var errs []string
+	length := int64(utf8.RuneCountInString(v))
+	if s.MaxLength != nil && length > *s.MaxLength {
+		errs = append(errs, fmt.Sprintf("%s: must be at most %d characters long", where, *s.MaxLength))
+	}
+	if s.MinLength != nil && length < *s.MinLength {
+		errs = append(errs, fmt.Sprintf("%s: must be at least %d characters long", where, *s.MinLength))
+	}
+	if s.Pattern != "" {
+		if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(v) {
+			errs = append(errs, fmt.Sprintf("%s: must match %q", where, s.Pattern))
+		}
+	}
+
+	return errs
+}
+
+func (c *schemas) validateArray(v []any, s *spec.Schema, where string) []string {
+	var errs []string
+	if s.MaxItems != nil && int64(len(v)) > *s.MaxItems {
+		errs = append(errs, fmt.Sprintf("%s: must have at most %d items", where, *s.MaxItems))
+	}
+	if s.MinItems != nil && int64(len(v)) < *s.MinItems {
+		errs = append(errs, fmt.Sprintf("%s: must have at least %d items", where, *s.MinItems))
+	}
+	if s.UniqueItems {
+		for i := range v {
+			if slices.ContainsFunc(v[:i], func(e any) bool { return reflect.DeepEqual(e, v[i]) }) {
+				errs = append(errs, where+": must have unique items")
+
+				break
+			}
+		}
+	}
+
+	if s.Items != nil && s.Items.Schema != nil {
+		for i, item := range v {
+			errs = append(errs, c.validateValue(item, s.Items.Schema, where+"."+strconv.Itoa(i))...)
+		}
+	}
+
+	return errs
+}
+
+func (c *schemas) validateObject(v map[string]any, s *spec.Schema, where string) []string {
+	var errs []string
+	for _, name := range s.Required {
+		if _, ok := v[name]; !ok {
+			errs = append(errs, where+"."+name+": is required")
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(v)) {
+		if property, ok := s.Properties[name]; ok {
+			errs = append(errs, c.validateValue(v[name], &property, where+"."+name)...)
+
+			continue
+		}
+
+		if additional := s.AdditionalProperties; additional != nil {
+			if additional.Schema != nil {
+				errs = append(errs, c.validateValue(v[name], additional.Schema, where+"."+name)...)
+			} else if !additional.Allows {
+				errs = append(errs, where+"."+name+": is not allowed")
+			}
+		}
+	}
+
+	return errs
+}
+
+// hasType tells if a JSON value is of a schema type.
+func hasType(value any, tpe string) bool {
+	switch v := value.(type) {
+	case map[string]any:
+		return tpe == "object"
+	case []any:
+		return tpe == "array"
+	case string:
+		return tpe == "string" || tpe == "file"
+	case bool:
+		return tpe == "boolean"
+	case float64:
+		return tpe == "number" || (tpe == "integer" && v == math.Trunc(v))
+	default:
+		return false
+	}
+}
+
+func isNullable(s *spec.Schema) bool {
+	nullable, _ := s.Extensions.GetBool("x-nullable")
+
+	return nullable
+}
+
+func formatEnum(enum []any) string {
+	values := make([]string, 0, len(enum))
+	for _, e := range enum {
+		data, _ := json.Marshal(e) //nolint:errchkjson // enum values are decoded from JSON
+
+		values = append(values, string(data))
+	}
+
+	return strings.Join(values, ", ")
+}
+
+func mediaTypeOf(contentType string) string {
+	base, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+
+	return base
+}
+
+// simpleSchema converts the definition of a non-body parameter, header or items to a schema.
+func simpleSchema(ss spec.SimpleSchema, cv spec.CommonValidations) *spec.Schema {
+	s := &spec.Schema{}
+	if ss.Type != "" {
+		s.Type = spec.StringOrArray{ss.Type}
+	}
+	s.Format = ss.Format
+	s.Default = ss.Default
+	s.Example = ss.Example
+	s.Nullable = ss.Nullable
+	s.Maximum, s.ExclusiveMaximum = cv.Maximum, cv.ExclusiveMaximum
+	s.Minimum, s.ExclusiveMinimum = cv.Minimum, cv.ExclusiveMinimum
+	s.MaxLength, s.MinLength, s.Pattern = cv.MaxLength, cv.MinLength, cv.Pattern
+	s.MaxItems, s.MinItems, s.UniqueItems = cv.MaxItems, cv.MinItems, cv.UniqueItems
+	s.MultipleOf = cv.MultipleOf
+	s.Enum = cv.Enum
+
+	if ss.Items != nil {
+		s.Items = &spec.SchemaOrArray{Schema: simpleSchema(ss.Items.SimpleSchema, ss.Items.CommonValidations)}
+	}
+
+	return s
+}
@@ -3,11 +3,21 @@
 
 package loads
 
-import "github.com/go-openapi/swag/loading"
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/go-openapi/swag/loading"
+)
 
 type options struct {
 	loader         *loader
 	loadingOptions []loading.Option
+	refs           refMapper
+	schemes        schemeDispatch
+	virtual        map[string]json.RawMessage
+	integrity      *IntegrityVerifier
 }
 
 func defaultOptions() *options {
@@ -24,6 +34,10 @@ func loaderFromOptions(options []LoaderOption) *loader {
 
 	l := opts.loader.clone()
 	l.loadingOptions = opts.loadingOptions
+	l.refs = opts.refs
+	l.schemes = opts.schemes
+	l.virtual = opts.virtual
+	l.integrity = opts.integrity
 
 	return l
 }
@@ -67,3 +81,63 @@ func WithLoadingOptions(loadingOptions ...loading.Option) LoaderOption {
 		opt.loadingOptions = loadingOptions
 	}
 }
+
+// LoaderSettings describes the loader configuration of a [Document].
+//
+// The configuration is set when the document is loaded, and carried over to every document derived
+// from it (e.g. with [Document.Expanded] or [Document.Pristine]).
+type LoaderSettings struct {
+	// LoadingOptions are passed to the loaders, e.g. as set by [WithLoadingOptions] or [SpecRestricted].
+	LoadingOptions []loading.Option
+
+	// RefMappings are the mappings set by [WithRefMapping] and [WithRefMappingFS].
+	RefMappings []RefMapping
+
+	// Offline is set by [WithOffline].
+	Offline bool
+
+	// Schemes lists the URI schemes with a loader set by [WithSchemeLoader], in lexical order.
+	Schemes []string
+
+	// DisabledSchemes lists the URI schemes disabled by [WithDisabledSchemes], in lexical order.
+	DisabledSchemes []string
+}
+
+// Loader returns the effective loader of the document: the loader that resolves its references.
+//
+// Loading options passed at call time are applied after the ones of the document.
+func (d *Document) Loader() DocLoader {
+	return d.docLoader().loadWithOptions
+}
+
+// LoaderSettings returns the loader configuration of the document.
+func (d *Document) LoaderSettings() LoaderSettings {
+	ldr := d.docLoader()
+
+	return LoaderSettings{
+		LoadingOptions:  slices.Clone(ldr.loadingOptions),
+		RefMappings:     slices.Clone(ldr.refs.mappings),
+		Offline:         ldr.refs.offline,
+		Schemes:         slices.Sorted(maps.Keys(ldr.schemes.loaders)),
+		DisabledSchemes: slices.Sorted(maps.Keys(ldr.schemes.disabled)),
+	}
+}
+
+// LoaderOptions returns the options that reproduce the loader configuration of the document, e.g. to
+// load another document the same way:
+//
+//	other, err := loads.Spec(path, document.LoaderOptions()...)
+func (d *Document) LoaderOptions() []LoaderOption {
+	ldr := d.docLoader()
+
+	return []LoaderOption{
+		func(opt *options) {
+			opt.loader = ldr
+			opt.loadingOptions = slices.Clone(ldr.loadingOptions)
+			opt.refs = refMapper{mappings: slices.Clone(ldr.refs.mappings), offline: ldr.refs.offline}
+			opt.schemes = ldr.schemes.clone()
+			opt.virtual = maps.Clone(ldr.virtual)
+			opt.integrity = ldr.integrity
+		},
+	}
+}
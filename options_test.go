@@ -121,3 +121,74 @@ func TestOptionsWithDocLoaderMatches(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, document)
 }
+
+func TestDocumentLoaderSettings(t *testing.T) {
+	document, err := Spec(mirroredPrefix+"spec.yml",
+		WithRefMapping(mirroredPrefix, "testdata/yaml/swagger"),
+		WithOffline(),
+		WithSchemeLoader("data", DataURIDoc),
+		WithDisabledSchemes("http", "file"),
+		WithLoadingOptions(loading.WithCustomHeaders(map[string]string{"X-Test": "1"})),
+	)
+	require.NoError(t, err)
+
+	settings := document.LoaderSettings()
+	assert.Len(t, settings.LoadingOptions, 1)
+	assert.Equal(t, []RefMapping{{Prefix: mirroredPrefix, Dir: "testdata/yaml/swagger"}}, settings.RefMappings)
+	assert.True(t, settings.Offline)
+	assert.Equal(t, []string{"data"}, settings.Schemes)
+	assert.Equal(t, []string{"file", "http"}, settings.DisabledSchemes)
+
+	expanded, err := document.Expanded()
+	require.NoError(t, err)
+
+	flattened, err := expanded.Flattened()
+	require.NoError(t, err)
+
+	derived := map[string]*Document{
+		"Expanded":                  expanded,
+		"Expanded/Flattened":        flattened,
+		"Expanded/Pristine":         expanded.Pristine(),
+		"Expanded/ResetDefinitions": expanded.ResetDefinitions(),
+		"Pristine/Expanded/Pristine": func() *Document {
+			again, err := document.Pristine().Expanded()
+			require.NoError(t, err)
+
+			return again.Pristine()
+		}(),
+	}
+
+	for name, dd := range derived {
+		t.Run(name+" should carry the loader configuration", func(t *testing.T) {
+			got := dd.LoaderSettings()
+			assert.Len(t, got.LoadingOptions, 1)
+			assert.Equal(t, settings.RefMappings, got.RefMappings)
+			assert.EqualT(t, settings.Offline, got.Offline)
+			assert.Equal(t, settings.Schemes, got.Schemes)
+			assert.Equal(t, settings.DisabledSchemes, got.DisabledSchemes)
+
+			_, err := dd.Loader()("https://example.com/spec.json")
+			require.ErrorIs(t, err, ErrOffline)
+
+			_, err = dd.Loader()(mirroredPrefix + "test3-ter-model-schema.json")
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("LoaderOptions should reproduce the configuration", func(t *testing.T) {
+		other, err := Spec(mirroredPrefix+"spec.yml", flattened.LoaderOptions()...)
+		require.NoError(t, err)
+
+		_, err = other.Expanded()
+		require.NoError(t, err)
+
+		_, err = Spec("https://example.com/spec.json", flattened.LoaderOptions()...)
+		require.ErrorIs(t, err, ErrOffline)
+
+		// later options apply on top, without altering the document
+		other, err = Spec(mirroredPrefix+"spec.yml", append(flattened.LoaderOptions(), WithDisabledSchemes("data"))...)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"data", "file", "http"}, other.LoaderSettings().DisabledSchemes)
+		assert.Equal(t, settings.DisabledSchemes, flattened.LoaderSettings().DisabledSchemes)
+	})
+}
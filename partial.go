@@ -0,0 +1,297 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// RefTarget describes a "$ref" considered for inlining during a partial expansion.
+type RefTarget struct {
+	// Ref is the reference, as found in the document.
+	Ref string
+
+	// Location is the JSON pointer of the "$ref" in the expanded document.
+	Location string
+
+	// Document is the resolved location of the target document.
+	Document string
+
+	// Fragment is the JSON pointer of the target within its document, percent-decoded.
+	Fragment string
+
+	// External is true when the target lives in another document than the root.
+	External bool
+
+	// Depth is the number of references already inlined above this one.
+	Depth int
+}
+
+// PartialExpandOption selects the references inlined by [Document.ExpandedPartial].
+type PartialExpandOption func(*partialExpandOptions)
+
+type partialExpandOptions struct {
+	predicates []func(RefTarget) bool
+	maxDepth   int
+	cycles     cyclePolicy
+}
+
+type cyclePolicy struct {
+	fail   bool
+	unroll int // number of times a cycle is inlined before it is kept as a "$ref"
+}
+
+// ExpandExternalOnly inlines references to other documents only, preserving local references
+// such as "#/definitions/model".
+func ExpandExternalOnly() PartialExpandOption {
+	return ExpandWhere(func(target RefTarget) bool {
+		return target.External
+	})
+}
+
+// ExpandTargetPrefix inlines references whose target JSON pointer starts with one of prefixes,
+// e.g. "/responses" to inline only response references.
+func ExpandTargetPrefix(prefixes ...string) PartialExpandOption {
+	return ExpandWhere(func(target RefTarget) bool {
+		return slices.ContainsFunc(prefixes, func(prefix string) bool {
+			return target.Fragment == prefix || strings.HasPrefix(target.Fragment, strings.TrimSuffix(prefix, "/")+"/")
+		})
+	})
+}
+
+// ExpandWhere inlines references for which the predicate holds.
+//
+// When several selectors are given, a reference is inlined only if all of them hold.
+func ExpandWhere(predicate func(RefTarget) bool) PartialExpandOption {
+	return func(o *partialExpandOptions) {
+		if predicate != nil {
+			o.predicates = append(o.predicates, predicate)
+		}
+	}
+}
+
+// ExpandMaxDepth limits the nesting of inlined references: a reference found within depth already
+// inlined references is preserved. A depth of 1 inlines only the references found in the document
+// itself.
+func ExpandMaxDepth(depth int) PartialExpandOption {
+	return func(o *partialExpandOptions) {
+		o.maxDepth = depth
+	}
+}
+
+// ExpandCyclesKeep preserves the "$ref" that closes a reference cycle, once the cycle has been
+// inlined once. This is the default.
+func ExpandCyclesKeep() PartialExpandOption {
+	return ExpandCyclesUnroll(1)
+}
+
+// ExpandCyclesFail makes the expansion fail with a [*CycleError] when it meets a reference cycle.
+//
+// Use [Document.Cycles] to report all the cycles of a document at once.
+func ExpandCyclesFail() PartialExpandOption {
+	return func(o *partialExpandOptions) {
+		o.cycles = cyclePolicy{fail: true}
+	}
+}
+
+// ExpandCyclesUnroll inlines a reference cycle up to depth times along a path, then preserves the
+// "$ref" that closes it. A depth of 1 is the same as [ExpandCyclesKeep].
+func ExpandCyclesUnroll(depth int) PartialExpandOption {
+	return func(o *partialExpandOptions) {
+		o.cycles = cyclePolicy{unroll: max(depth, 1)}
+	}
+}
+
+// ExpandedPartial inlines a selection of the "$ref"s in the document and returns a new [Document].
+//
+// Unlike [Document.Expanded], which inlines every reference, only the references selected by opts
+// are inlined: without any option, all references are. Reference cycles are inlined once, then
+// preserved as a "$ref", unless configured otherwise with [ExpandCyclesFail] or [ExpandCyclesUnroll].
+//
+// The remaining references are kept intact and valid: references that originate from an inlined
+// external document are rewritten relative to the document, so that the result may be expanded
+// further.
+//
+// References are resolved through the document's loader, like with [Document.Expanded].
+func (d *Document) ExpandedPartial(opts ...PartialExpandOption) (*Document, error) {
+	var o partialExpandOptions
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	root, err := decodeJSON(d.Raw())
+	if err != nil {
+		return nil, errLoads(err)
+	}
+
+	p := &partialExpander{
+		options:  o,
+		rootKey:  lockKey(d.specFilePath),
+		rootBase: d.specFilePath,
+		documents: refDocuments{
+			loader: d.docLoader(),
+			docs:   map[string]any{lockKey(d.specFilePath): root},
+		},
+	}
+
+	expanded, err := p.expand(deepCopy(root), d.specFilePath, "", "", 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(expanded)
+	if err != nil {
+		return nil, errLoads(err)
+	}
+
+	swspec := new(spec.Swagger)
+	if err := json.Unmarshal(raw, swspec); err != nil {
+		return nil, errLoads(err)
+	}
+
+	return d.derive(swspec), nil
+}
+
+type partialExpander struct {
+	options   partialExpandOptions
+	rootKey   string
+	rootBase  string
+	documents refDocuments
+}
+
+// expand walks node, located at JSON pointer source in the document at base, and at JSON pointer
+// location in the result.
+func (p *partialExpander) expand(node any, base, source, location string, depth int, stack []refFrame) (any, error) {
+	switch n := node.(type) {
+	case map[string]any:
+		if ref, ok := n[refKey].(string); ok {
+			return p.expandRef(n, RefLocation{Document: base, Pointer: source, Ref: ref}, location, depth, stack)
+		}
+
+		for k, v := range n {
+			token := escapePointerToken(k)
+			expanded, err := p.expand(v, base, source+"/"+token, location+"/"+token, depth, stack)
+			if err != nil {
+				return nil, err
+			}
+			n[k] = expanded
+		}
+
+		return n, nil
+	case []any:
+		for i, v := range n {
+			token := strconv.Itoa(i)
+			expanded, err := p.expand(v, base, source+"/"+token, location+"/"+token, depth, stack)
+			if err != nil {
+				return nil, err
+			}
+			n[i] = expanded
+		}
+
+		return n, nil
+	default:
+		return n, nil
+	}
+}
+
+func (p *partialExpander) expandRef(holder map[string]any, from RefLocation, location string, depth int, stack []refFrame) (any, error) {
+	document, fragment := splitRef(from.Ref)
+	fragment = decodeFragment(fragment)
+	targetDoc := resolveDocument(from.Document, document)
+	targetKey := lockKey(targetDoc)
+	target := RefTarget{
+		Ref:      from.Ref,
+		Location: location,
+		Document: targetDoc,
+		Fragment: fragment,
+		External: targetKey != p.rootKey,
+		Depth:    depth,
+	}
+	id := targetKey + "#" + fragment
+
+	inline := p.selected(target)
+	if inline {
+		if cycle, found := cycleOf(stack, id, from); found {
+			if p.options.cycles.fail {
+				return nil, &CycleError{Cycle: cycle}
+			}
+
+			inline = p.unrolled(stack, id) < max(p.options.cycles.unroll, 1)
+		}
+	}
+
+	if !inline {
+		// preserved: rewrite the reference so that it remains valid from the root document
+		holder[refKey] = p.relativeRef(targetDoc, targetKey, fragment)
+
+		return holder, nil
+	}
+
+	resolved, err := p.documents.resolve(targetDoc, fragment)
+	if err != nil {
+		return nil, errLoads(fmt.Errorf("resolving %q at %q: %w", from.Ref, location, err))
+	}
+
+	stack = append(slices.Clone(stack), refFrame{target: id, from: from})
+
+	return p.expand(deepCopy(resolved), targetDoc, fragment, location, depth+1, stack)
+}
+
+// unrolled counts how many times the target id is being inlined.
+func (p *partialExpander) unrolled(stack []refFrame, id string) int {
+	var n int
+	for _, frame := range stack {
+		if frame.target == id {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (p *partialExpander) selected(target RefTarget) bool {
+	if p.options.maxDepth > 0 && target.Depth >= p.options.maxDepth {
+		return false
+	}
+
+	for _, predicate := range p.options.predicates {
+		if !predicate(target) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// relativeRef expresses a reference to pointer in the document at targetDoc, relative to the root document.
+func (p *partialExpander) relativeRef(targetDoc, targetKey, pointer string) string {
+	fragment := encodeFragment(pointer)
+	if targetKey == p.rootKey {
+		return "#" + fragment
+	}
+
+	if isRemote(targetDoc) || isRemote(p.rootBase) || p.rootBase == "" {
+		return targetDoc + "#" + fragment
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(p.rootKey)), filepath.FromSlash(targetKey))
+	if err != nil {
+		return targetDoc + "#" + fragment
+	}
+
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, ".") && !path.IsAbs(rel) {
+		rel = "./" + rel
+	}
+
+	return rel + "#" + fragment
+}
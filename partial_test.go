@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestExpandedPartial(t *testing.T) {
+	t.Run("should inline external references only", func(t *testing.T) {
+		document, err := Spec("testdata/yaml/swagger/spec.yml")
+		require.NoError(t, err)
+
+		partial, err := document.ExpandedPartial(ExpandExternalOnly())
+		require.NoError(t, err)
+
+		sp := partial.Spec()
+		param := sp.Paths.Paths["/getAll"].Get.Parameters[0]
+		assert.EqualT(t, "#/definitions/a", param.Schema.Ref.String())
+		response := sp.Paths.Paths["/getAll"].Get.Responses.StatusCodeResponses[200]
+		assert.EqualT(t, "#/definitions/b", response.Schema.Ref.String())
+		b := sp.Definitions["b"]
+		assert.EqualT(t, "", b.Ref.String())
+		assert.True(t, b.Type.Contains("array"))
+
+		// the remaining references are valid: the partially expanded document expands fully
+		expanded, err := partial.Expanded()
+		require.NoError(t, err)
+		assert.JSONMarshalAsT(t, cascadeRefExpanded, expanded.Spec())
+
+		// the receiver is left untouched
+		original := document.Spec().Definitions["b"]
+		assert.EqualT(t, "./test3-ter-model-schema.json#/definitions/b", original.Ref.String())
+	})
+
+	t.Run("should inline all references without a selector", func(t *testing.T) {
+		document, err := Spec("testdata/yaml/swagger/spec.yml")
+		require.NoError(t, err)
+
+		partial, err := document.ExpandedPartial()
+		require.NoError(t, err)
+		assert.JSONMarshalAsT(t, cascadeRefExpanded, partial.Spec())
+	})
+
+	t.Run("should resolve percent-encoded pointers", func(t *testing.T) {
+		document, err := Spec("testdata/json/encoded-pointers.json")
+		require.NoError(t, err)
+
+		partial, err := document.ExpandedPartial()
+		require.NoError(t, err)
+		schema := partial.Spec().Paths.Paths["/items"].Get.Responses.StatusCodeResponses[200].Schema
+		require.NotNil(t, schema)
+		assert.True(t, schema.Type.Contains("object"))
+		assert.True(t, schema.Properties["c"].Type.Contains("string"))
+
+		partial, err = document.ExpandedPartial(ExpandTargetPrefix("/definitions/c~1d"))
+		require.NoError(t, err)
+		schema = partial.Spec().Paths.Paths["/items"].Get.Responses.StatusCodeResponses[200].Schema
+		assert.EqualT(t, "#/definitions/a%20b", schema.Ref.String())
+		inlined := partial.Spec().Definitions["a b"].Properties["c"]
+		assert.True(t, inlined.Type.Contains("string"))
+
+		_, err = partial.Expanded()
+		require.NoError(t, err)
+	})
+
+	t.Run("should inline references by target prefix", func(t *testing.T) {
+		document, err := Spec("testdata/json/partial/spec.json")
+		require.NoError(t, err)
+
+		partial, err := document.ExpandedPartial(ExpandTargetPrefix("/responses"))
+		require.NoError(t, err)
+
+		op := partial.Spec().Paths.Paths["/items"].Get
+		notFound := op.Responses.StatusCodeResponses[404]
+		assert.EqualT(t, "", notFound.Ref.String())
+		assert.EqualT(t, "not found", notFound.Description)
+		ok := op.Responses.StatusCodeResponses[200]
+		assert.EqualT(t, "", ok.Ref.String())
+		assert.EqualT(t, "list of items", ok.Description)
+		// references found in inlined content and not selected are preserved
+		assert.EqualT(t, "#/definitions/Item", ok.Schema.Items.Schema.Ref.String())
+	})
+
+	t.Run("should inline references matching a predicate", func(t *testing.T) {
+		document, err := Spec("testdata/json/partial/spec.json")
+		require.NoError(t, err)
+
+		var seen []string
+		partial, err := document.ExpandedPartial(ExpandWhere(func(target RefTarget) bool {
+			seen = append(seen, target.Location)
+
+			return strings.HasSuffix(target.Fragment, "/NotFound")
+		}))
+		require.NoError(t, err)
+
+		responses := partial.Spec().Paths.Paths["/items"].Get.Responses.StatusCodeResponses
+		notFound, ok := responses[404], responses[200]
+		assert.EqualT(t, "", notFound.Ref.String())
+		assert.EqualT(t, "#/responses/Items", ok.Ref.String())
+		assert.Contains(t, seen, "/paths/~1items/get/responses/404")
+	})
+
+	t.Run("should stop at the maximum depth and on cycles", func(t *testing.T) {
+		document, err := Spec("testdata/json/partial/spec.json")
+		require.NoError(t, err)
+
+		partial, err := document.ExpandedPartial(ExpandMaxDepth(1))
+		require.NoError(t, err)
+
+		ok := partial.Spec().Paths.Paths["/items"].Get.Responses.StatusCodeResponses[200]
+		assert.EqualT(t, "", ok.Ref.String())
+		assert.EqualT(t, "#/definitions/Item", ok.Schema.Items.Schema.Ref.String())
+
+		full, err := document.ExpandedPartial()
+		require.NoError(t, err)
+
+		item := full.Spec().Definitions["Item"]
+		assert.EqualT(t, "", item.Properties["children"].Items.Schema.Ref.String())
+		// the cycle is broken with a reference back to the definition
+		assert.EqualT(t, "#/definitions/Item",
+			item.Properties["children"].Items.Schema.Properties["children"].Items.Schema.Ref.String())
+	})
+
+	t.Run("should report an unresolvable reference", func(t *testing.T) {
+		document, err := Spec("testdata/json/partial/unresolved.json")
+		require.NoError(t, err)
+
+		_, err = document.ExpandedPartial()
+		require.ErrorIs(t, err, ErrLoads)
+		require.ErrorIs(t, err, ErrInvalidRef)
+	})
+}
@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const refKey = "$ref"
+
+// decodeJSON decodes a JSON document into a generic tree of maps and slices, preserving numbers
+// as [json.Number].
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var node any
+	if err := dec.Decode(&node); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// walkRefs calls fn for every "$ref" found in a generic JSON tree, in a deterministic order.
+//
+// fn receives the object holding the "$ref" key, so that the reference may be rewritten in place.
+func walkRefs(node any, fn func(holder map[string]any, ref string)) {
+	switch n := node.(type) {
+	case map[string]any:
+		if ref, ok := n[refKey].(string); ok {
+			fn(n, ref)
+		}
+
+		keys := make([]string, 0, len(n))
+		for k := range n {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			walkRefs(n[k], fn)
+		}
+	case []any:
+		for _, e := range n {
+			walkRefs(e, fn)
+		}
+	}
+}
+
+// splitRef splits a "$ref" into its document part and its JSON pointer fragment.
+//
+// A local reference (e.g. "#/definitions/a") yields an empty document part.
+func splitRef(ref string) (document, fragment string) {
+	document, fragment, _ = strings.Cut(ref, "#")
+
+	return document, fragment
+}
+
+// resolveDocument resolves the document part of a "$ref" against the location of the document
+// holding it, and returns the location of the target document.
+//
+// Remote bases are resolved as URLs. Local bases are resolved as slash-separated paths, so
+// relative locations stay relative (unlike [url.URL.ResolveReference], which roots them).
+func resolveDocument(base, document string) string {
+	if document == "" {
+		return base
+	}
+
+	if isRemote(document) || path.IsAbs(filepath.ToSlash(document)) || filepath.IsAbs(document) {
+		return document
+	}
+
+	if isRemote(base) {
+		u, err := url.Parse(base)
+		if err != nil {
+			return document
+		}
+
+		r, err := url.Parse(document)
+		if err != nil {
+			return document
+		}
+
+		return u.ResolveReference(r).String()
+	}
+
+	if base == "" {
+		return path.Clean(filepath.ToSlash(document))
+	}
+
+	return path.Join(path.Dir(filepath.ToSlash(strings.TrimPrefix(base, "file://"))), filepath.ToSlash(document))
+}
+
+// decodeFragment percent-decodes the fragment of a URI, which is then read as a JSON pointer
+// (RFC 6901, section 6). A fragment with invalid escapes is returned as is.
+func decodeFragment(fragment string) string {
+	if decoded, err := url.PathUnescape(fragment); err == nil {
+		return decoded
+	}
+
+	return fragment
+}
+
+// encodeFragment percent-encodes a JSON pointer to be written as the fragment of a "$ref".
+func encodeFragment(pointer string) string {
+	return (&url.URL{Fragment: pointer}).EscapedFragment()
+}
+
+// resolvePointer returns the node designated by a JSON pointer fragment (e.g. "/definitions/a") in a generic JSON tree.
+//
+// The fragment of a "$ref" is percent-decoded with [decodeFragment] first, as a whole.
+func resolvePointer(node any, fragment string) (any, error) {
+	if fragment == "" {
+		return node, nil
+	}
+
+	if !strings.HasPrefix(fragment, "/") {
+		return nil, fmt.Errorf("%w: invalid JSON pointer %q", ErrInvalidRef, fragment)
+	}
+
+	current := node
+	for token := range strings.SplitSeq(fragment[1:], "/") {
+		token = unescapePointerToken(token)
+
+		switch n := current.(type) {
+		case map[string]any:
+			child, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: JSON pointer %q: key %q not found", ErrInvalidRef, fragment, token)
+			}
+			current = child
+		case []any:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(n) {
+				return nil, fmt.Errorf("%w: JSON pointer %q: invalid index %q", ErrInvalidRef, fragment, token)
+			}
+			current = n[idx]
+		default:
+			return nil, fmt.Errorf("%w: JSON pointer %q: cannot traverse a scalar at %q", ErrInvalidRef, fragment, token)
+		}
+	}
+
+	return current, nil
+}
+
+// escapePointerToken escapes a key to be used as a JSON pointer token.
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// unescapePointerToken decodes a JSON pointer token, as escaped by [escapePointerToken].
+func unescapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// deepCopy copies a generic JSON tree.
+func deepCopy(node any) any {
+	switch n := node.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(n))
+		for k, v := range n {
+			m[k] = deepCopy(v)
+		}
+
+		return m
+	case []any:
+		s := make([]any, len(n))
+		for i, v := range n {
+			s[i] = deepCopy(v)
+		}
+
+		return s
+	default:
+		return n
+	}
+}
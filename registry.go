@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+
+	"github.com/go-openapi/swag/loading"
+)
+
+// Registry is a self-contained loader configuration: a loader chain with its own default options.
+//
+// It is the scoped counterpart of the package-level configuration ([AddLoader], [SetLoaders],
+// [SetRestrictedLoaders]): documents loaded through a Registry only ever use its own chain, for
+// the initial load and for every "$ref" resolved by [Document.Expanded]. Different registries may
+// thus carry different confinement policies, e.g. one per tenant in a multi-tenant server, without
+// touching any global.
+//
+// A Registry is safe for concurrent use: loads may run while the chain or the default options are
+// updated. A load uses the configuration as it was when the load started.
+type Registry struct {
+	mu      sync.RWMutex
+	chain   *loader
+	options []LoaderOption
+}
+
+// NewRegistry builds a [Registry] with the built-in loader chain (a YAML matcher with a JSON
+// fallback, see [DefaultLoaders]) and opts as its default options.
+func NewRegistry(opts ...LoaderOption) *Registry {
+	return &Registry{
+		chain:   defaultLoaders(),
+		options: slices.Clone(opts),
+	}
+}
+
+// AddLoader prepends a loader to the chain of the registry, like [AddLoader] does for the package-level chain.
+func (r *Registry) AddLoader(predicate DocMatcher, load DocLoader) {
+	if load == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	head := &loader{DocLoaderWithMatch: NewDocLoaderWithMatch(load, predicate)}
+	r.chain = r.chain.WithHead(head)
+}
+
+// SetLoaders replaces the chain of the registry, like [SetLoaders] does for the package-level chain.
+//
+// Calling SetLoaders with no usable loader restores the built-in default.
+func (r *Registry) SetLoaders(ldrs ...DocLoaderWithMatch) {
+	chain := buildLoaderChain(ldrs...)
+	if chain == nil {
+		chain = defaultLoaders()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.chain = chain
+}
+
+// SetRestrictedLoaders installs a confined JSON/YAML chain rooted at root in the registry, like
+// [SetRestrictedLoaders] does for the package-level chain.
+func (r *Registry) SetRestrictedLoaders(root string, opts ...loading.Option) {
+	r.SetLoaders(restrictedLoaders(root, opts)...)
+}
+
+// SetOptions replaces the default options of the registry, applied to every load before the
+// options passed at call time.
+func (r *Registry) SetOptions(opts ...LoaderOption) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.options = slices.Clone(opts)
+}
+
+// Spec loads a spec document like [Spec], using the chain and the default options of the registry.
+func (r *Registry) Spec(path string, opts ...LoaderOption) (*Document, error) {
+	return Spec(path, r.with(opts)...)
+}
+
+// JSONSpec loads a JSON spec document like [JSONSpec], using the chain and the default options of the registry.
+func (r *Registry) JSONSpec(path string, opts ...LoaderOption) (*Document, error) {
+	return JSONSpec(path, r.with(opts)...)
+}
+
+// Analyzed creates a new analyzed spec document like [Analyzed], using the chain and the default
+// options of the registry to resolve references.
+func (r *Registry) Analyzed(data json.RawMessage, version string, opts ...LoaderOption) (*Document, error) {
+	return Analyzed(data, version, r.with(opts)...)
+}
+
+// Load loads a raw document through the chain of the registry, with its default options.
+//
+// It is a [DocLoader], so a registry may be passed to [WithDocLoader] or installed as
+// [github.com/go-openapi/spec.PathLoader]. Call-time options are applied after the default options.
+func (r *Registry) Load(path string, opts ...loading.Option) (json.RawMessage, error) {
+	all := r.with(nil)
+	if len(opts) > 0 {
+		all = append(all, appendLoadingOptions(opts))
+	}
+
+	return loaderFromOptions(all).Load(path)
+}
+
+// with returns the options for a load: a snapshot of the chain, the default options, then opts.
+func (r *Registry) with(opts []LoaderOption) []LoaderOption {
+	r.mu.RLock()
+	chain := r.chain
+	defaults := r.options
+	r.mu.RUnlock()
+
+	all := make([]LoaderOption, 0, 1+len(defaults)+len(opts))
+	all = append(all, withChain(chain))
+	all = append(all, defaults...)
+	all = append(all, opts...)
+
+	return all
+}
+
+// withChain sets the loader chain. The chain is never mutated in place: loaderFromOptions clones it.
+func withChain(chain *loader) LoaderOption {
+	return func(opt *options) {
+		opt.loader = chain
+	}
+}
+
+// appendLoadingOptions adds loading options after the ones already set.
+func appendLoadingOptions(loadingOptions []loading.Option) LoaderOption {
+	return func(opt *options) {
+		opt.loadingOptions = append(slices.Clone(opt.loadingOptions), loadingOptions...)
+	}
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag/loading"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("should load with the built-in chain by default", func(t *testing.T) {
+		registry := loads.NewRegistry()
+
+		doc, err := registry.Spec("testdata/yaml/swagger/spec.yml")
+		require.NoError(t, err)
+
+		_, err = doc.Expanded()
+		require.NoError(t, err)
+
+		doc, err = registry.JSONSpec("testdata/json/petstore-basic.json")
+		require.NoError(t, err)
+		assert.Equal(t, "petstore.swagger.wordnik.com", doc.Host())
+	})
+
+	t.Run("should scope confinement to each registry", func(t *testing.T) {
+		yamlTenant := loads.NewRegistry()
+		yamlTenant.SetRestrictedLoaders("testdata/yaml")
+		jsonTenant := loads.NewRegistry()
+		jsonTenant.SetRestrictedLoaders("testdata/json")
+
+		_, err := yamlTenant.Spec("swagger/spec.yml")
+		require.NoError(t, err)
+		_, err = jsonTenant.Spec("swagger/spec.yml")
+		require.Error(t, err)
+
+		_, err = jsonTenant.Spec("petstore-basic.json")
+		require.NoError(t, err)
+		_, err = yamlTenant.Spec("petstore-basic.json")
+		require.Error(t, err)
+
+		// the initial load of a JSON spec is confined as well
+		_, err = jsonTenant.JSONSpec("petstore-basic.json")
+		require.NoError(t, err)
+		outside, err := filepath.Abs("testdata/yaml/swagger/test3-ter-model-schema.json")
+		require.NoError(t, err)
+		_, err = jsonTenant.JSONSpec(outside)
+		require.Error(t, err)
+		_, err = jsonTenant.JSONSpec("../yaml/swagger/test3-ter-model-schema.json")
+		require.Error(t, err)
+
+		// the package-level default is untouched
+		_, err = loads.Spec("testdata/json/petstore-basic.json")
+		require.NoError(t, err)
+	})
+
+	t.Run("should prepend loaders and apply default options", func(t *testing.T) {
+		var called atomic.Int32
+		registry := loads.NewRegistry(loads.WithOffline())
+		registry.AddLoader(loading.JSONMatcher, func(pth string, opts ...loading.Option) (json.RawMessage, error) {
+			called.Add(1)
+
+			return loads.JSONDoc(pth, opts...)
+		})
+
+		_, err := registry.Spec("testdata/json/petstore-basic.json")
+		require.NoError(t, err)
+		assert.EqualT(t, int32(1), called.Load())
+
+		_, err = registry.Spec("https://example.com/spec.json")
+		require.ErrorIs(t, err, loads.ErrOffline)
+
+		registry.SetOptions()
+		registry.SetLoaders() // back to the built-in chain
+		_, err = registry.Spec("testdata/json/petstore-basic.json")
+		require.NoError(t, err)
+		assert.EqualT(t, int32(1), called.Load())
+	})
+
+	t.Run("should serve as a DocLoader", func(t *testing.T) {
+		registry := loads.NewRegistry()
+		registry.SetRestrictedLoaders("testdata/json")
+
+		doc, err := loads.Spec("petstore-basic.json", loads.WithDocLoader(registry.Load))
+		require.NoError(t, err)
+		assert.Equal(t, "petstore.swagger.wordnik.com", doc.Host())
+
+		_, err = registry.Load("../yaml/swagger/spec.yml")
+		require.Error(t, err)
+
+		_, err = registry.Analyzed(doc.Raw(), "")
+		require.NoError(t, err)
+	})
+
+	t.Run("should not touch spec.PathLoader", func(t *testing.T) {
+		var called bool
+		registry := loads.NewRegistry()
+		registry.SetLoaders(loads.NewDocLoaderWithMatch(func(pth string, opts ...loading.Option) (json.RawMessage, error) {
+			called = true
+
+			return loads.JSONDoc(pth, opts...)
+		}, nil))
+
+		_, err := spec.PathLoader("testdata/json/petstore-basic.json")
+		require.NoError(t, err)
+		assert.False(t, called)
+	})
+}
+
+func TestRegistryConcurrency(t *testing.T) {
+	registry := loads.NewRegistry()
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Go(func() {
+			for range 10 {
+				switch i % 4 {
+				case 0:
+					registry.SetLoaders(loads.DefaultLoaders()...)
+				case 1:
+					registry.AddLoader(loading.JSONMatcher, loads.JSONDoc)
+				case 2:
+					registry.SetOptions(loads.WithLoadingOptions())
+				default:
+					doc, err := registry.Spec("testdata/yaml/swagger/spec.yml")
+					assert.NoError(t, err)
+					if err == nil {
+						_, err = doc.Expanded()
+						assert.NoError(t, err)
+					}
+				}
+			}
+		})
+	}
+
+	wg.Wait()
+}
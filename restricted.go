@@ -176,10 +176,10 @@ func SpecRestricted(path, root string, opts ...loading.Option) (*Document, error
 // not safe to call concurrently. Configure it once at startup, before serving. To revert, call
 // [SetLoaders] with no arguments.
 func SetRestrictedLoaders(root string, opts ...loading.Option) {
-	base := restrictedLoadingOptions(root, opts) // one restricted client shared by the whole chain
+	SetLoaders(restrictedLoaders(root, opts)...)
+}
 
-	SetLoaders(
-		NewDocLoaderWithMatch(restrictedDocLoader(loading.YAMLDoc, base), loading.YAMLMatcher),
-		NewDocLoaderWithMatch(restrictedDocLoader(JSONDoc, base), nil), // nil matcher: JSON catch-all fallback
-	)
+// restrictedLoaders builds the confined JSON/YAML loader chain rooted at root.
+func restrictedLoaders(root string, opts []loading.Option) []DocLoaderWithMatch {
+	return newRestrictedRoots([]string{root}, nil, opts).loaders() // one restricted client shared by the whole chain
 }
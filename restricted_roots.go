@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-openapi/swag/loading"
+)
+
+// RootReporter is notified by a multi-root restricted loader of the root that satisfied a load.
+//
+// root is empty for a remote document, which is not confined to any root.
+type RootReporter func(path, root string)
+
+// RestrictedRootsLoaders returns a confined JSON/YAML loader chain, like the one installed by
+// [SetRestrictedLoaders], but with a set of read-only roots instead of a single one.
+//
+// A local document is looked up in every root in turn, and the first root that holds it wins. A
+// document mapped by [WithRefMapping] is looked up in its mirror directory only, which must lie
+// within one of the roots.
+// Each root offers the same protections as the single-root loaders (via
+// [github.com/go-openapi/swag/loading.WithRoot]): absolute paths outside the root, ".." traversal
+// and symlinks escaping the root are all rejected. This allows, for instance, a service spec to
+// reference a shared "common" tree that lives outside the service directory, without opening
+// access to anything else. Remote documents are fetched with [RestrictedHTTPClient].
+//
+// Relative roots are made absolute. When report is not nil, it is called after each successful
+// load with the root that satisfied it.
+//
+// The returned loaders may be passed to [WithDocLoaderMatches] or [SetLoaders].
+func RestrictedRootsLoaders(roots []string, report RootReporter, opts ...loading.Option) []DocLoaderWithMatch {
+	return newRestrictedRoots(roots, report, opts).loaders() // one restricted client shared by the whole chain
+}
+
+// JSONDocRestrictedRoots is the multi-root counterpart of [JSONDocRestricted]: it returns a JSON
+// [DocLoader] that confines local reads to a set of roots. See [RestrictedRootsLoaders].
+func JSONDocRestrictedRoots(roots []string, opts ...loading.Option) DocLoader {
+	return newRestrictedRoots(roots, nil, opts).docLoader(JSONDoc)
+}
+
+// JSONSpecRestrictedRoots is the multi-root counterpart of [JSONSpecRestricted]. See [RestrictedRootsLoaders].
+func JSONSpecRestrictedRoots(path string, roots []string, opts ...loading.Option) (*Document, error) {
+	return JSONSpec(path, WithDocLoader(JSONDocRestrictedRoots(roots, opts...)))
+}
+
+// SpecRestrictedRoots is the multi-root counterpart of [SpecRestricted]. See [RestrictedRootsLoaders].
+func SpecRestrictedRoots(path string, roots []string, opts ...loading.Option) (*Document, error) {
+	return Spec(path, WithDocLoaderMatches(RestrictedRootsLoaders(roots, nil, opts...)...))
+}
+
+// SetRestrictedLoadersRoots is the multi-root counterpart of [SetRestrictedLoaders]. See [RestrictedRootsLoaders].
+//
+// # Concurrency
+//
+// Like [SetLoaders], this mutates package-level and [github.com/go-openapi/spec] globals and is
+// not safe to call concurrently.
+func SetRestrictedLoadersRoots(roots []string, opts ...loading.Option) {
+	SetLoaders(RestrictedRootsLoaders(roots, nil, opts...)...)
+}
+
+type restrictedRoots struct {
+	roots  []string
+	report RootReporter
+	extra  []loading.Option
+	client *http.Client
+}
+
+func newRestrictedRoots(roots []string, report RootReporter, extra []loading.Option) *restrictedRoots {
+	absRoots := make([]string, 0, len(roots))
+	for _, root := range roots {
+		if abs, err := filepath.Abs(root); err == nil {
+			root = abs
+		}
+		absRoots = append(absRoots, root)
+	}
+
+	return &restrictedRoots{
+		roots:  absRoots,
+		report: report,
+		extra:  extra,
+		client: RestrictedHTTPClient(),
+	}
+}
+
+// options bundles call-time options, extra options and the confinement to root, appended last so
+// that the confinement always takes precedence (loading options are last-wins).
+func (r *restrictedRoots) options(root string, callOpts []loading.Option) []loading.Option {
+	all := make([]loading.Option, 0, len(callOpts)+len(r.extra)+numConfinementOptions)
+	all = append(all, callOpts...)
+	all = append(all, r.extra...)
+	all = append(all, loading.WithRoot(root), loading.WithHTTPClient(r.client))
+
+	return all
+}
+
+// loaders builds the confined JSON/YAML loader chain.
+func (r *restrictedRoots) loaders() []DocLoaderWithMatch {
+	fetch := r.docLoader(JSONDoc)
+	ldrs := []DocLoaderWithMatch{
+		newFetchingDocLoader(fetch, yamlToJSON, loading.YAMLMatcher),
+		newFetchingDocLoader(fetch, asJSON, nil), // nil matcher: JSON catch-all fallback
+	}
+
+	for i := range ldrs {
+		convert, match := ldrs[i].convert, ldrs[i].Match
+		ldrs[i].mirrored = func(mapping RefMapping) (DocLoaderWithMatch, error) {
+			if mapping.FS != nil {
+				// the file system of the mirror is its own confinement
+				return newFetchingDocLoader(r.fsDocLoader(JSONDoc, mapping.FS), convert, match), nil
+			}
+
+			mirror, err := r.within(mapping.Dir)
+			if err != nil {
+				return DocLoaderWithMatch{}, err
+			}
+
+			return newFetchingDocLoader(mirror.docLoader(JSONDoc), convert, match), nil
+		}
+	}
+
+	return ldrs
+}
+
+// within returns the restriction to dir, provided it lies within one of the roots.
+//
+// Symbolic links are resolved on both sides, so that dir cannot point outside of the roots.
+func (r *restrictedRoots) within(dir string) (*restrictedRoots, error) {
+	resolved, err := filepath.Abs(dir)
+	if err == nil {
+		resolved, err = filepath.EvalSymlinks(resolved)
+	}
+	if err != nil {
+		return nil, errLoads(err)
+	}
+
+	for _, root := range r.roots {
+		root, err := filepath.EvalSymlinks(root)
+		if err != nil {
+			continue
+		}
+
+		rel, err := filepath.Rel(root, resolved)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+
+		return &restrictedRoots{roots: []string{resolved}, report: r.report, extra: r.extra, client: r.client}, nil
+	}
+
+	return nil, errLoads(fmt.Errorf("%w: mirror %q is outside the allowed roots", ErrNoLoader, dir))
+}
+
+// fsDocLoader reads documents from fsys only, instead of the roots.
+func (r *restrictedRoots) fsDocLoader(fn DocLoader, fsys fs.FS) DocLoader {
+	return func(path string, callOpts ...loading.Option) (json.RawMessage, error) {
+		all := make([]loading.Option, 0, len(callOpts)+len(r.extra)+numConfinementOptions)
+		all = append(all, callOpts...)
+		all = append(all, r.extra...)
+		all = append(all, loading.WithFS(fsys), loading.WithHTTPClient(r.client))
+
+		return fn(path, all...)
+	}
+}
+
+func (r *restrictedRoots) docLoader(fn DocLoader) DocLoader {
+	return func(path string, callOpts ...loading.Option) (json.RawMessage, error) {
+		if len(r.roots) == 0 {
+			return nil, errLoads(fmt.Errorf("%w: no root configured for %q", ErrNoLoader, path))
+		}
+
+		if isRemote(path) {
+			b, err := fn(path, r.options(r.roots[0], callOpts)...)
+			if err == nil && r.report != nil {
+				r.report(path, "")
+			}
+
+			return b, err
+		}
+
+		var lastErr error
+		for _, root := range r.roots {
+			b, err := fn(path, r.options(root, callOpts)...)
+			if err != nil {
+				lastErr = err
+
+				continue
+			}
+
+			if r.report != nil {
+				r.report(path, root)
+			}
+
+			return b, nil
+		}
+
+		return nil, errLoads(lastErr)
+	}
+}
@@ -0,0 +1,207 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+// monorepo lays out a service spec referencing a shared tree outside the service directory,
+// next to a secret that no root covers.
+func monorepo(t *testing.T) (service, common, secret string) {
+	t.Helper()
+
+	repo := t.TempDir()
+	service = filepath.Join(repo, "services", "pets")
+	common = filepath.Join(repo, "common")
+	require.NoError(t, os.MkdirAll(service, 0o750))
+	require.NoError(t, os.MkdirAll(common, 0o750))
+
+	require.NoError(t, os.WriteFile(filepath.Join(service, "spec.yaml"), []byte(`swagger: "2.0"
+info: {title: pets, version: "1"}
+paths: {}
+definitions:
+  pet:
+    $ref: ../../common/models.yaml#/definitions/pet
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(common, "models.yaml"), []byte(`definitions:
+  pet:
+    type: object
+    properties:
+      name: {type: string}
+`), 0o600))
+
+	secret = filepath.Join(repo, "secret.json")
+	require.NoError(t, os.WriteFile(secret, []byte(`{"secret":true}`), 0o600))
+
+	return service, common, secret
+}
+
+func TestSpecRestrictedRoots(t *testing.T) {
+	service, common, secret := monorepo(t)
+	roots := []string{service, common}
+
+	t.Run("should resolve references into a shared root", func(t *testing.T) {
+		doc, err := loads.SpecRestrictedRoots(filepath.Join(service, "spec.yaml"), roots)
+		require.NoError(t, err)
+
+		expanded, err := doc.Expanded()
+		require.NoError(t, err)
+		pet := expanded.Spec().Definitions["pet"]
+		assert.Contains(t, pet.Properties, "name")
+	})
+
+	t.Run("should fail with a single root", func(t *testing.T) {
+		doc, err := loads.SpecRestricted(filepath.Join(service, "spec.yaml"), service)
+		require.NoError(t, err)
+
+		_, err = doc.Expanded()
+		require.Error(t, err)
+	})
+
+	t.Run("should reject a path outside every root", func(t *testing.T) {
+		_, err := loads.SpecRestrictedRoots(secret, roots)
+		require.Error(t, err)
+
+		_, err = loads.SpecRestrictedRoots(filepath.Join(service, "..", "..", "secret.json"), roots)
+		require.Error(t, err)
+
+		_, err = loads.JSONSpecRestrictedRoots("../../secret.json", roots)
+		require.Error(t, err)
+	})
+
+	t.Run("should reject a symlink escaping a root", func(t *testing.T) {
+		link := filepath.Join(common, "escape.json")
+		if err := os.Symlink(secret, link); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+		t.Cleanup(func() { _ = os.Remove(link) })
+
+		_, err := loads.JSONDocRestrictedRoots(roots)(link)
+		require.Error(t, err)
+	})
+
+	t.Run("should block a loopback remote URL", func(t *testing.T) {
+		srv := serveSomeJSONDocument()
+		defer srv.Close()
+
+		_, err := loads.SpecRestrictedRoots(srv.URL, roots)
+		require.ErrorIs(t, err, loads.ErrForbiddenAddress)
+	})
+
+	t.Run("should fail closed without any root", func(t *testing.T) {
+		_, err := loads.JSONDocRestrictedRoots(nil)(filepath.Join(service, "spec.yaml"))
+		require.ErrorIs(t, err, loads.ErrNoLoader)
+	})
+}
+
+func TestRestrictedRootsLoaders(t *testing.T) {
+	service, common, _ := monorepo(t)
+
+	var (
+		mx       sync.Mutex
+		resolved = make(map[string]string)
+	)
+	report := func(path, root string) {
+		mx.Lock()
+		defer mx.Unlock()
+		resolved[filepath.Base(path)] = root
+	}
+
+	doc, err := loads.Spec(filepath.Join(service, "spec.yaml"),
+		loads.WithDocLoaderMatches(loads.RestrictedRootsLoaders([]string{service, common}, report)...),
+	)
+	require.NoError(t, err)
+
+	_, err = doc.Expanded()
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"spec.yaml":   service,
+		"models.yaml": common,
+	}, resolved)
+}
+
+func TestSetRestrictedLoadersRoots(t *testing.T) {
+	t.Cleanup(func() { loads.SetLoaders() }) // restore the built-in default
+
+	service, common, secret := monorepo(t)
+	loads.SetRestrictedLoadersRoots([]string{service, common})
+
+	doc, err := loads.Spec(filepath.Join(service, "spec.yaml"))
+	require.NoError(t, err)
+
+	_, err = doc.Expanded()
+	require.NoError(t, err)
+
+	_, err = loads.Spec(secret)
+	require.Error(t, err)
+}
+
+func TestRestrictedRootsWithRefMapping(t *testing.T) {
+	const prefix = "https://example.com/mirror/"
+
+	service, common, secret := monorepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(service, "mapped.yaml"), []byte(`swagger: "2.0"
+info: {title: pets, version: "1"}
+paths: {}
+definitions:
+  pet:
+    $ref: `+prefix+`models.yaml#/definitions/pet
+`), 0o600))
+
+	load := func(mirror string, roots ...string) error {
+		doc, err := loads.Spec(filepath.Join(service, "mapped.yaml"),
+			loads.WithDocLoaderMatches(loads.RestrictedRootsLoaders(roots, nil)...),
+			loads.WithRefMapping(prefix, mirror),
+			loads.WithOffline(),
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = doc.Expanded()
+
+		return err
+	}
+
+	t.Run("should look up mapped documents in the mirror", func(t *testing.T) {
+		require.NoError(t, load(common, t.TempDir(), service, common))
+	})
+
+	t.Run("should reject a mirror outside every root", func(t *testing.T) {
+		require.ErrorIs(t, load(common, service), loads.ErrNoLoader)
+		require.Error(t, load(filepath.Dir(secret), service, common))
+	})
+
+	t.Run("should look up mapped documents in a mirror file system", func(t *testing.T) {
+		mirror := fstest.MapFS{
+			"models.yaml": &fstest.MapFile{Data: []byte(`definitions:
+  pet:
+    type: object
+    properties:
+      name: {type: string}
+`)},
+		}
+
+		doc, err := loads.Spec(filepath.Join(service, "mapped.yaml"),
+			loads.WithDocLoaderMatches(loads.RestrictedRootsLoaders([]string{service}, nil)...),
+			loads.WithRefMappingFS(prefix, mirror),
+			loads.WithOffline(),
+		)
+		require.NoError(t, err)
+
+		expanded, err := doc.Expanded()
+		require.NoError(t, err)
+		assert.Contains(t, expanded.Spec().Definitions["pet"].Properties, "name")
+	})
+}
@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"strings"
+
+	"github.com/go-openapi/swag/loading"
+	"github.com/go-openapi/swag/yamlutils"
+)
+
+const (
+	// schemeFile is the scheme of local documents, whether designated by a plain path or a "file://" URI.
+	schemeFile = "file"
+
+	schemeData = "data"
+)
+
+// schemeDispatch routes loads by URI scheme, before the matchers of the loader chain are tried.
+type schemeDispatch struct {
+	loaders  map[string]DocLoader
+	disabled map[string]struct{}
+}
+
+func (s schemeDispatch) clone() schemeDispatch {
+	return schemeDispatch{
+		loaders:  maps.Clone(s.loaders),
+		disabled: maps.Clone(s.disabled),
+	}
+}
+
+// WithSchemeLoader registers a loader for all documents with the given URI scheme (e.g. "data",
+// "s3", "git"). The scheme is matched case-insensitively on the parsed URL.
+//
+// Local documents, designated by a plain path or a "file://" URI, have the scheme "file".
+//
+// Scheme loaders take precedence over the loaders of the chain, and apply to the initial load as
+// well as to every "$ref" resolved during [Document.Expanded]. The loading options supplied with
+// [WithLoadingOptions] are passed to them.
+func WithSchemeLoader(scheme string, fn DocLoader) LoaderOption {
+	return func(opt *options) {
+		if fn == nil {
+			return
+		}
+
+		if opt.schemes.loaders == nil {
+			opt.schemes.loaders = make(map[string]DocLoader)
+		}
+		opt.schemes.loaders[strings.ToLower(scheme)] = fn
+	}
+}
+
+// WithDisabledSchemes refuses to load any document with one of the given URI schemes, with [ErrSchemeDisabled].
+//
+// For example, WithDisabledSchemes("http") forbids plain-text remote loads while still allowing
+// "https", and WithDisabledSchemes("file") forbids any local read. Documents redirected to a local
+// mirror by [WithRefMapping] are not subject to the disabled schemes.
+func WithDisabledSchemes(schemes ...string) LoaderOption {
+	return func(opt *options) {
+		if opt.schemes.disabled == nil {
+			opt.schemes.disabled = make(map[string]struct{}, len(schemes))
+		}
+
+		for _, scheme := range schemes {
+			opt.schemes.disabled[strings.ToLower(scheme)] = struct{}{}
+		}
+	}
+}
+
+// MatchSchemes builds a [DocMatcher] matching documents by the scheme of their parsed URL.
+//
+// Use "file" to match local documents. Unlike a raw string predicate, a local file named e.g.
+// "https.json" is not mistaken for a remote document.
+func MatchSchemes(schemes ...string) DocMatcher {
+	set := make(map[string]struct{}, len(schemes))
+	for _, scheme := range schemes {
+		set[strings.ToLower(scheme)] = struct{}{}
+	}
+
+	return func(path string) bool {
+		_, ok := set[schemeOf(path)]
+
+		return ok
+	}
+}
+
+// dispatch returns the loader registered for the scheme of path, if any.
+//
+// It fails with [ErrSchemeDisabled] if this scheme is disabled.
+func (s schemeDispatch) dispatch(path string) (DocLoader, error) {
+	scheme := schemeOf(path)
+	if _, disabled := s.disabled[scheme]; disabled {
+		return nil, errLoads(fmt.Errorf("%w: %q in %q", ErrSchemeDisabled, scheme, path))
+	}
+
+	return s.loaders[scheme], nil
+}
+
+// schemeOf returns the lower-cased scheme of path, or "file" for a local path.
+//
+// A single-letter scheme is a windows drive letter (e.g. "C:\folder") and is considered local.
+func schemeOf(path string) string {
+	u, err := url.Parse(path)
+	if err != nil || len(u.Scheme) <= 1 {
+		return schemeFile
+	}
+
+	return strings.ToLower(u.Scheme)
+}
+
+// DataURIDoc loads a document embedded in a "data:" URI (RFC 2397), such as
+//
+//	data:application/json;base64,eyJzd2FnZ2VyIjoiMi4wIn0=
+//
+// Both base64 and percent-encoded payloads are supported. YAML content is converted to JSON.
+//
+// No loading option applies: the document is decoded from the URI itself and no I/O is performed.
+// It is intended to be registered with [WithSchemeLoader]:
+//
+//	loads.Spec(uri, loads.WithSchemeLoader("data", loads.DataURIDoc))
+func DataURIDoc(path string, _ ...loading.Option) (json.RawMessage, error) {
+	rest, ok := cutPrefixFold(path, schemeData+":")
+	if !ok {
+		return nil, fmt.Errorf("%w: not a data URI: %q", ErrLoads, path)
+	}
+
+	rest, _, _ = strings.Cut(rest, "#") // a fragment designates a part of the document, not its content
+
+	header, payload, ok := strings.Cut(rest, ",")
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid data URI: missing ',' in %q", ErrLoads, path)
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if strings.HasSuffix(strings.ToLower(header), ";base64") {
+		data, err = base64.StdEncoding.DecodeString(payload)
+	} else {
+		var unescaped string
+		unescaped, err = url.PathUnescape(payload)
+		data = []byte(unescaped)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid data URI payload: %w", ErrLoads, err)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return json.RawMessage(trimmed), nil
+	}
+
+	yml, err := yamlutils.BytesToYAMLDoc(trimmed)
+	if err != nil {
+		return nil, errLoads(err)
+	}
+
+	doc, err := yamlutils.YAMLToJSON(yml)
+	if err != nil {
+		return nil, errLoads(err)
+	}
+
+	return doc, nil
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+
+	return s[len(prefix):], true
+}
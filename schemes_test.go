@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/swag/loading"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestSchemeOf(t *testing.T) {
+	for pth, expected := range map[string]string{
+		"spec.json":                 "file",
+		"/abs/spec.json":            "file",
+		"file:///abs/spec.json":     "file",
+		`C:\folder\spec.json`:       "file",
+		"https.json":                "file",
+		"HTTPS://example.com/a.yml": "https",
+		"http://example.com":        "http",
+		"s3://bucket/key.json":      "s3",
+		"data:,{}":                  "data",
+	} {
+		assert.EqualTf(t, expected, schemeOf(pth), "unexpected scheme for %q", pth)
+	}
+}
+
+func TestDataURIDoc(t *testing.T) {
+	t.Run("should decode a base64 JSON payload", func(t *testing.T) {
+		uri := "data:application/json;base64," + base64.StdEncoding.EncodeToString(petStoreJSON)
+
+		b, err := DataURIDoc(uri)
+		require.NoError(t, err)
+		assert.JSONEqT(t, string(petStoreJSON), string(b))
+	})
+
+	t.Run("should decode a percent-encoded YAML payload", func(t *testing.T) {
+		uri := "data:application/yaml," + url.PathEscape("swagger: '2.0'\ninfo: {title: t, version: '1'}\n")
+
+		b, err := DataURIDoc(uri)
+		require.NoError(t, err)
+		assert.JSONEqT(t, `{"swagger":"2.0","info":{"title":"t","version":"1"}}`, string(b))
+	})
+
+	t.Run("should reject invalid data URIs", func(t *testing.T) {
+		for _, uri := range []string{"spec.json", "data:application/json", "data:;base64,!!!"} {
+			_, err := DataURIDoc(uri)
+			require.Errorf(t, err, "expected %q to be rejected", uri)
+			require.ErrorIs(t, err, ErrLoads)
+		}
+	})
+
+	t.Run("should load a spec from a data URI when registered", func(t *testing.T) {
+		uri := "data:application/json;base64," + base64.StdEncoding.EncodeToString(petStoreJSON)
+
+		document, err := Spec(uri, WithSchemeLoader("data", DataURIDoc))
+		require.NoError(t, err)
+		assert.EqualT(t, "petstore.swagger.wordnik.com", document.Host())
+
+		// offline mode does not apply to data URIs
+		_, err = Spec(uri, WithSchemeLoader("data", DataURIDoc), WithOffline())
+		require.NoError(t, err)
+	})
+}
+
+func TestWithSchemeLoader(t *testing.T) {
+	// a stand-in for an object store, backed by a local directory
+	standIn := func(calls *[]string) DocLoader {
+		return func(pth string, opts ...loading.Option) (json.RawMessage, error) {
+			*calls = append(*calls, pth)
+			key := strings.TrimPrefix(pth, "s3://bucket/")
+
+			return LoaderChain(DefaultLoaders()...)(filepath.Join("testdata", "yaml", "swagger", key), opts...)
+		}
+	}
+
+	t.Run("should dispatch the initial load and references to the scheme loader", func(t *testing.T) {
+		var calls []string
+		document, err := Spec("s3://bucket/spec.yml", WithSchemeLoader("S3", standIn(&calls)))
+		require.NoError(t, err)
+
+		expanded, err := document.Expanded()
+		require.NoError(t, err)
+		assert.JSONMarshalAsT(t, cascadeRefExpanded, expanded.Spec())
+		assert.Equal(t, []string{"s3://bucket/spec.yml", "s3://bucket/test3-ter-model-schema.json"}, calls)
+	})
+
+	t.Run("should not affect other schemes", func(t *testing.T) {
+		var calls []string
+		_, err := Spec("testdata/yaml/swagger/spec.yml", WithSchemeLoader("s3", standIn(&calls)))
+		require.NoError(t, err)
+		assert.Empty(t, calls)
+	})
+}
+
+func TestWithDisabledSchemes(t *testing.T) {
+	t.Run("should refuse local documents when file is disabled", func(t *testing.T) {
+		_, err := Spec("testdata/yaml/swagger/spec.yml", WithDisabledSchemes("file"))
+		require.ErrorIs(t, err, ErrSchemeDisabled)
+
+		_, err = JSONSpec("testdata/json/petstore-basic.json", WithDisabledSchemes("FILE"))
+		require.ErrorIs(t, err, ErrSchemeDisabled)
+	})
+
+	t.Run("should refuse remote documents when http is disabled", func(t *testing.T) {
+		var hits int
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			hits++
+			_, _ = rw.Write(petStoreJSON)
+		}))
+		defer srv.Close()
+
+		_, err := Spec(srv.URL, WithDisabledSchemes("http"))
+		require.ErrorIs(t, err, ErrSchemeDisabled)
+		assert.EqualT(t, 0, hits)
+
+		_, err = Spec(srv.URL, WithDisabledSchemes("https"))
+		require.NoError(t, err)
+	})
+
+	t.Run("should refuse disabled references during expansion", func(t *testing.T) {
+		document, err := Spec("testdata/yaml/swagger/spec.yml")
+		require.NoError(t, err)
+
+		document.pathLoader.schemes = schemeDispatch{disabled: map[string]struct{}{"file": {}}}
+		_, err = document.Expanded()
+		require.ErrorIs(t, err, ErrSchemeDisabled)
+	})
+
+	t.Run("should still serve mapped documents", func(t *testing.T) {
+		_, err := Spec(mirroredPrefix+"spec.yml",
+			WithRefMapping(mirroredPrefix, "testdata/yaml/swagger"),
+			WithDisabledSchemes("http", "https"),
+		)
+		require.NoError(t, err)
+	})
+}
+
+func TestMatchSchemes(t *testing.T) {
+	remote := MatchSchemes("http", "https")
+	assert.True(t, remote("https://example.com/spec.json"))
+	assert.False(t, remote("https.json"))
+	assert.False(t, remote("httpbin/spec.json"))
+
+	local := MatchSchemes("file")
+	assert.True(t, local("spec.json"))
+	assert.True(t, local("file:///spec.json"))
+	assert.False(t, local("https://example.com/spec.json"))
+}
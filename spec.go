@@ -8,7 +8,7 @@ import (
 	"encoding/gob"
 	"encoding/json"
 	"fmt"
-	"maps"
+	"sync"
 
 	"github.com/go-openapi/analysis"
 	"github.com/go-openapi/spec"
@@ -21,42 +21,69 @@ func init() {
 }
 
 // Document represents a swagger spec document.
+//
+// A Document holds two views of the spec:
+//   - the source: the document as it was loaded ([Document.SourceRaw]) and its object model ([Document.OrigSpec]);
+//   - the current spec: its object model ([Document.Spec]), the same as JSON ([Document.SpecRaw]) and its analysis (Analyzer).
+//
+// [Document.Raw] is the current spec as JSON too, except for a document built with [Embedded], for
+// which it is the source.
+//
+// Derivations ([Document.Expanded], [Document.ExpandedPartial], [Document.Flattened],
+// [Document.Modified], [Document.ResetDefinitions] and [Document.Pristine]) never alter the
+// receiver: they return a new Document, with the same source, and a current spec, raw bytes and
+// analyzer that are consistent with each other.
+//
+// [Document.Spec] returns the object model itself: altering it in place leaves [Document.Raw] and
+// the Analyzer behind, and affects every goroutine using the document. Use [Document.Modified] to
+// alter a copy instead, or [Document.Clone] to obtain an independent document.
+//
+// # Concurrency
+//
+// No method of Document alters the receiver, so a Document may be used by several goroutines at
+// once, as long as none of them alters it in place: through the results of [Document.Spec],
+// [Document.OrigSpec] and [Document.Raw], or by assigning the Analyzer. To share a document with
+// code that could, share a read-only view obtained with [Document.Freeze].
 type Document struct {
 	// specAnalyzer
 	Analyzer     *analysis.Spec
 	spec         *spec.Swagger
 	specFilePath string
-	origSpec     *spec.Swagger
 	schema       *spec.Schema
 	pathLoader   *loader
-	raw          json.RawMessage
+	raw          json.RawMessage // when nil, the current spec is the source
+	specRaw      json.RawMessage // the current spec, when raw is not (see Embedded)
+	source       *docSource
 }
 
-// JSONSpec loads a spec from a JSON document, using the [JSONDoc] loader.
+// JSONSpec loads a spec from a JSON document.
 //
-// A set of [loading.Option] may be passed to this loader using [WithLoadingOptions].
+// The document is loaded through the loader chain set by the options ([WithDocLoader],
+// [WithDocLoaderMatches]) or, by default, the package-level chain, so that the initial load is
+// confined like the "$ref"s resolved later. A set of [loading.Option] may be passed to the loaders
+// using [WithLoadingOptions].
 func JSONSpec(path string, opts ...LoaderOption) (*Document, error) {
-	var o options
-	for _, apply := range opts {
-		apply(&o)
-	}
-
-	data, err := JSONDoc(path, o.loadingOptions...)
+	fetched, err := loaderFromOptions(opts).fetchDocument(path)
 	if err != nil {
 		return nil, err
 	}
 	// convert to json
-	doc, err := Analyzed(data, "", opts...)
+	doc, err := Analyzed(fetched.json, "", opts...)
 	if err != nil {
 		return nil, err
 	}
 
 	doc.specFilePath = path
+	doc.source.fetched = fetched.raw
 
 	return doc, nil
 }
 
 // Embedded returns a Document based on embedded specs (i.e. as a [json.RawMessage]). No analysis is required.
+//
+// orig is the source of the document, returned by [Document.Raw] and expanded by [Document.Expanded]
+// (go-swagger servers serve it as "/swagger.json"). flat is its current spec, returned by
+// [Document.SpecRaw].
 func Embedded(orig, flat json.RawMessage, opts ...LoaderOption) (*Document, error) {
 	var origSpec, flatSpec spec.Swagger
 	if err := json.Unmarshal(orig, &origSpec); err != nil {
@@ -67,7 +94,8 @@ func Embedded(orig, flat json.RawMessage, opts ...LoaderOption) (*Document, erro
 	}
 	return &Document{
 		raw:        orig,
-		origSpec:   &origSpec,
+		specRaw:    flat,
+		source:     &docSource{raw: orig, spec: &origSpec},
 		spec:       &flatSpec,
 		pathLoader: loaderFromOptions(opts),
 	}, nil
@@ -87,17 +115,18 @@ func Embedded(orig, flat json.RawMessage, opts ...LoaderOption) (*Document, erro
 func Spec(path string, opts ...LoaderOption) (*Document, error) {
 	ldr := loaderFromOptions(opts)
 
-	b, err := ldr.Load(path)
+	fetched, err := ldr.fetchDocument(path)
 	if err != nil {
 		return nil, err
 	}
 
-	document, err := Analyzed(b, "", opts...)
+	document, err := Analyzed(fetched.json, "", opts...)
 	if err != nil {
 		return nil, err
 	}
 
 	document.specFilePath = path
+	document.source.fetched = fetched.raw
 	document.pathLoader = ldr
 
 	return document, nil
@@ -132,13 +161,55 @@ func Analyzed(data json.RawMessage, version string, options ...LoaderOption) (*D
 		schema:     spec.MustLoadSwagger20Schema(),
 		spec:       swspec,
 		raw:        raw,
-		origSpec:   origsqspec,
+		source:     &docSource{raw: raw, spec: origsqspec},
 		pathLoader: loaderFromOptions(options),
 	}
 
 	return d, nil
 }
 
+// NewDocument creates a new analyzed spec document for a spec built programmatically, e.g. with a
+// [DocumentBuilder], and the loader configured by opts to resolve its references.
+//
+// The document takes ownership of swspec, which must not be altered afterwards (see [Document.Modified]).
+// It is used as the current spec without parsing it back from JSON: it is only encoded once, for
+// [Document.Raw] and as the source of the document. An empty version defaults to "2.0", without altering
+// swspec.
+func NewDocument(swspec *spec.Swagger, opts ...LoaderOption) (*Document, error) {
+	if swspec == nil {
+		return nil, fmt.Errorf("%w: nil spec", ErrLoads)
+	}
+
+	if swspec.Swagger == "" {
+		versioned := *swspec // a shallow copy, so that the caller's spec is left untouched
+		versioned.Swagger = "2.0"
+		swspec = &versioned
+	}
+	if swspec.Swagger != "2.0" {
+		return nil, fmt.Errorf("%w: spec version %q is not supported", ErrLoads, swspec.Swagger)
+	}
+
+	return decodedDocument(swspec, opts...)
+}
+
+// decodedDocument builds a document for an already decoded spec, which it takes ownership of.
+// The spec is encoded once, as the raw bytes and the source of the document.
+func decodedDocument(swspec *spec.Swagger, opts ...LoaderOption) (*Document, error) {
+	raw, err := json.Marshal(swspec)
+	if err != nil {
+		return nil, errLoads(err)
+	}
+
+	return &Document{
+		Analyzer:   analysis.New(swspec),
+		schema:     spec.MustLoadSwagger20Schema(),
+		spec:       swspec,
+		raw:        raw,
+		source:     &docSource{raw: raw}, // the original spec is decoded from raw on first use
+		pathLoader: loaderFromOptions(opts),
+	}, nil
+}
+
 func trimData(in json.RawMessage) (json.RawMessage, error) {
 	trimmed := bytes.TrimSpace(in)
 	if len(trimmed) == 0 {
@@ -169,50 +240,39 @@ func trimData(in json.RawMessage) (json.RawMessage, error) {
 // the spec contents drive further loads. A spec from an untrusted source can thus trigger
 // arbitrary local reads or SSRF through its references. The loader carries the
 // [github.com/go-openapi/swag/loading] options supplied via [WithLoadingOptions] at load time;
-// configure confinement there so it applies to expansion as well. When no document loader is
-// set, expansion falls back to the unconfined package-level loader. See the package
-// documentation on Security.
+// configure confinement there so it applies to expansion as well. Unless options explicitly
+// supply a loader, every reference is resolved by the document's own loader — including those
+// resolved by nested calls in [github.com/go-openapi/spec], which never fall back to the
+// [github.com/go-openapi/spec.PathLoader] global. Only a document built without any loader falls
+// back to the unconfined package-level loader. See the package documentation on Security.
+//
+// The options passed by the caller are not modified.
 func (d *Document) Expanded(options ...*spec.ExpandOptions) (*Document, error) {
 	swspec := new(spec.Swagger)
-	if err := json.Unmarshal(d.raw, swspec); err != nil {
+	if err := json.Unmarshal(d.Raw(), swspec); err != nil {
 		return nil, err
 	}
 
-	var expandOptions *spec.ExpandOptions
-	if len(options) > 0 {
-		expandOptions = options[0]
-		if expandOptions.RelativeBase == "" {
-			expandOptions.RelativeBase = d.specFilePath
-		}
-	} else {
-		expandOptions = &spec.ExpandOptions{
-			RelativeBase: d.specFilePath,
-		}
+	expandOptions := new(spec.ExpandOptions)
+	if len(options) > 0 && options[0] != nil {
+		*expandOptions = *options[0] // the caller's options are left untouched
 	}
 
-	if expandOptions.PathLoader == nil {
-		if d.pathLoader != nil {
-			// use loader from Document options
-			expandOptions.PathLoader = d.pathLoader.Load
-		} else {
-			// use package level loader
-			expandOptions.PathLoader = loaders.Load
-		}
+	if expandOptions.RelativeBase == "" {
+		expandOptions.RelativeBase = d.specFilePath
+	}
+
+	if expandOptions.PathLoader == nil && expandOptions.PathLoaderWithOptions == nil {
+		// every "$ref", including those resolved by nested calls in the spec package, goes through
+		// the document's own loader, never through the spec.PathLoader global
+		expandOptions.PathLoader = d.docLoader().Load
 	}
 
 	if err := spec.ExpandSpec(swspec, expandOptions); err != nil {
 		return nil, err
 	}
 
-	dd := &Document{
-		Analyzer:     analysis.New(swspec),
-		spec:         swspec,
-		specFilePath: d.specFilePath,
-		schema:       spec.MustLoadSwagger20Schema(),
-		raw:          d.raw,
-		origSpec:     d.origSpec,
-	}
-	return dd, nil
+	return d.derive(swspec), nil
 }
 
 // BasePath the base path for the API specified by this spec.
@@ -243,39 +303,98 @@ func (d *Document) Host() string {
 	return d.spec.Host
 }
 
-// Raw returns the raw swagger spec as json bytes.
+// Raw returns the current swagger spec as json bytes, i.e. [Document.Spec] as JSON.
+//
+// For a document built with [Embedded], Raw returns the source of the document instead. See
+// [Document.SourceRaw] for the document as it was loaded.
 func (d *Document) Raw() json.RawMessage {
+	if d.raw == nil {
+		return d.source.rawJSON()
+	}
+
 	return d.raw
 }
 
-// OrigSpec yields the original spec.
+// SpecRaw returns the current swagger spec as json bytes, i.e. [Document.Spec] as JSON.
+//
+// It is the same as [Document.Raw], except for a document built with [Embedded], for which it
+// returns the flattened spec.
+func (d *Document) SpecRaw() json.RawMessage {
+	if d.specRaw != nil {
+		return d.specRaw
+	}
+
+	return d.Raw()
+}
+
+// SourceRaw returns the swagger spec as json bytes, as it was loaded.
+//
+// Documents derived from this one (e.g. with [Document.Expanded]) share the same source.
+func (d *Document) SourceRaw() json.RawMessage {
+	return d.source.rawJSON()
+}
+
+// OrigSpec yields the original spec, i.e. the object model of [Document.SourceRaw].
 func (d *Document) OrigSpec() *spec.Swagger {
-	return d.origSpec
+	return d.source.object()
 }
 
-// ResetDefinitions yields a shallow copy with the models reset to the original spec.
+// ResetDefinitions yields a new document with the models reset to the original spec.
+//
+// The receiver is left unchanged: the new document holds a deep copy of the spec.
 func (d *Document) ResetDefinitions() *Document {
-	d.spec.Definitions = make(map[string]spec.Schema, len(d.origSpec.Definitions))
-	maps.Copy(d.spec.Definitions, d.origSpec.Definitions)
+	swspec := copySpec(d.spec)
+	swspec.Definitions = copySpec(d.OrigSpec()).Definitions
+	if swspec.Definitions == nil {
+		swspec.Definitions = make(map[string]spec.Schema)
+	}
 
-	return d
+	return d.derive(swspec)
 }
 
-// Pristine creates a new pristine document instance based on the input data.
+// Pristine creates a new pristine document instance based on the current spec, which is analyzed anew.
+//
+// The source of the document is preserved.
 func (d *Document) Pristine() *Document {
 	raw, _ := json.Marshal(d.Spec()) //nolint:errchkjson  // the spec always marshals to JSON
 	dd, _ := Analyzed(raw, d.Version())
 	dd.pathLoader = d.pathLoader
 	dd.specFilePath = d.specFilePath
+	dd.source = d.source
 
 	return dd
 }
 
+// derive builds a new document for swspec, with the same source and loader as the receiver.
+func (d *Document) derive(swspec *spec.Swagger) *Document {
+	raw, _ := json.Marshal(swspec) //nolint:errchkjson  // the spec always marshals to JSON
+
+	return &Document{
+		Analyzer:     analysis.New(swspec),
+		spec:         swspec,
+		specFilePath: d.specFilePath,
+		schema:       spec.MustLoadSwagger20Schema(),
+		raw:          raw,
+		source:       d.source,
+		pathLoader:   d.pathLoader,
+	}
+}
+
 // SpecFilePath returns the file path of the spec if one is defined.
 func (d *Document) SpecFilePath() string {
 	return d.specFilePath
 }
 
+// docLoader returns the loader configured for this document, or the package level loader
+// when none is set.
+func (d *Document) docLoader() *loader {
+	if d.pathLoader != nil {
+		return d.pathLoader
+	}
+
+	return loaders
+}
+
 func cloneSpec(src *spec.Swagger) (*spec.Swagger, error) {
 	var b bytes.Buffer
 	if err := gob.NewEncoder(&b).Encode(src); err != nil {
@@ -289,3 +408,54 @@ func cloneSpec(src *spec.Swagger) (*spec.Swagger, error) {
 
 	return &dst, nil
 }
+
+// docSource is the source of a document: the document as it was loaded, and its object model.
+//
+// It is shared by all the documents derived from the same load. When only raw is known, e.g. for a
+// streamed document (see [AnalyzedStream]), the spec is decoded from it on first use.
+type docSource struct {
+	once    sync.Once
+	raw     json.RawMessage
+	spec    *spec.Swagger // when nil, decoded from raw on first use
+	fetched []byte        // the document as fetched from its path (e.g. YAML), when loaded from a path
+}
+
+func (s *docSource) load() {
+	s.once.Do(func() {
+		if s.spec == nil && s.raw != nil {
+			s.spec = new(spec.Swagger)
+			_ = json.Unmarshal(s.raw, s.spec) // the spec always unmarshals from its own JSON
+		}
+	})
+}
+
+func (s *docSource) rawJSON() json.RawMessage {
+	if s == nil {
+		return nil
+	}
+	s.load()
+
+	return s.raw
+}
+
+// fetchedRaw returns the document as fetched from its path, or its JSON source when it was not fetched.
+func (s *docSource) fetchedRaw() []byte {
+	if s == nil {
+		return nil
+	}
+
+	if s.fetched != nil {
+		return s.fetched
+	}
+
+	return s.rawJSON()
+}
+
+func (s *docSource) object() *spec.Swagger {
+	if s == nil {
+		return nil
+	}
+	s.load()
+
+	return s.spec
+}
@@ -124,7 +124,20 @@ func TestEmbedded(t *testing.T) {
 	require.NotNil(t, d)
 
 	assert.JSONMarshalAsT(t, raw, d.Raw())
+	assert.JSONMarshalAsT(t, raw, d.SourceRaw())
+	assert.JSONMarshalAsT(t, spc, d.SpecRaw())
 	assert.JSONMarshalAsT(t, spc, d.Spec())
+
+	t.Run("should expand the source of an embedded document", func(t *testing.T) {
+		flat := json.RawMessage(`{"swagger":"2.0","info":{"title":"flat","version":"1"},"host":"flat.example.com","paths":{}}`)
+		d, err := Embedded(petStoreJSON, flat)
+		require.NoError(t, err)
+		require.EqualT(t, "flat.example.com", d.Host())
+
+		expanded, err := d.Expanded()
+		require.NoError(t, err)
+		assert.EqualT(t, "petstore.swagger.wordnik.com", expanded.Host())
+	})
 }
 
 func TestDocument(t *testing.T) {
@@ -147,6 +160,61 @@ func TestDocument(t *testing.T) {
 	require.JSONMarshalAsT(t, petStoreJSON, reset.Spec())
 }
 
+func TestDocumentCoherence(t *testing.T) {
+	document, err := Spec("testdata/yaml/swagger/spec.yml")
+	require.NoError(t, err)
+	source := document.SourceRaw()
+	raw := document.Raw()
+
+	derive := map[string]func() (*Document, error){
+		"Expanded": func() (*Document, error) {
+			return document.Expanded()
+		},
+		"ExpandedPartial": func() (*Document, error) {
+			return document.ExpandedPartial(ExpandExternalOnly())
+		},
+		"Flattened": func() (*Document, error) {
+			return document.Flattened()
+		},
+		"ResetDefinitions": func() (*Document, error) {
+			return document.ResetDefinitions(), nil
+		},
+		"Pristine": func() (*Document, error) {
+			return document.Pristine(), nil
+		},
+	}
+
+	for name, deriveFn := range derive {
+		t.Run(name, func(t *testing.T) {
+			derived, err := deriveFn()
+			require.NoError(t, err)
+			require.NotSame(t, document, derived)
+
+			// the current raw bytes, spec and analyzer agree
+			current, err := json.Marshal(derived.Spec())
+			require.NoError(t, err)
+			assert.JSONEqT(t, string(current), string(derived.Raw()))
+			assert.Len(t, derived.Analyzer.AllRefs(), strings.Count(string(derived.Raw()), `"$ref"`))
+
+			// the source is shared
+			assert.Equal(t, source, derived.SourceRaw())
+			assert.Same(t, document.OrigSpec(), derived.OrigSpec())
+
+			// the receiver is left untouched
+			assert.Equal(t, raw, document.Raw())
+			original := document.Spec().Definitions["b"]
+			assert.EqualT(t, "./test3-ter-model-schema.json#/definitions/b", original.Ref.String())
+		})
+	}
+
+	t.Run("should inline every reference when expanded", func(t *testing.T) {
+		expanded, err := document.Expanded()
+		require.NoError(t, err)
+		assert.JSONEqT(t, string(cascadeRefExpanded), string(expanded.Raw()))
+		assert.NotContains(t, string(expanded.Raw()), "$ref")
+	})
+}
+
 func TestSpecCircular(t *testing.T) {
 	swaggerFile := "testdata/json/resources/pathLoaderIssue.json"
 	document, err := Spec(swaggerFile)
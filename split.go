@@ -0,0 +1,237 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// splitSections are the sections of named components written to their own file by [Document.Split].
+var splitSections = []string{"definitions", "parameters", "responses", "paths"}
+
+// inlineOnlySections are the split sections whose entries cannot be references (Swagger 2.0 requires
+// inline parameter and response definitions): they are dropped from the root document once split.
+var inlineOnlySections = map[string]struct{}{"parameters": {}, "responses": {}}
+
+// Split writes the document into dir as a tree of files: every definition, shared parameter, shared
+// response and path item is written to its own file, under a subdirectory named after its section
+// (e.g. dir/definitions/Pet.json).
+//
+// The root document refers to the files of definitions and path items with relative "$ref"s. Swagger
+// 2.0 does not allow a "$ref" in place of a shared parameter or response definition: these are only
+// referred to from where they are used, e.g. by the operations, and no longer declared in the root
+// document.
+//
+// This is the inverse of bundling: loading the root document with [Spec] yields a spec [Equivalent] to
+// the original, and expanding it yields the same spec as the expanded original but for the shared
+// parameters and responses, and for circular references, which are preserved as references to the
+// split files.
+//
+// Local references are rewritten to point at the files holding their target, and references to
+// other documents are rewritten relative to the file holding them, so that they still resolve.
+// Files are written as JSON. The root document is named after the document, like with
+// [Document.Vendor].
+//
+// Split returns the paths of the written files, relative to dir and slash-separated, the root
+// document first.
+func (d *Document) Split(dir string) ([]string, error) {
+	node, err := decodeJSON(d.Raw())
+	if err != nil {
+		return nil, errLoads(err)
+	}
+
+	root, ok := node.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: the document is not an object", ErrLoads)
+	}
+
+	s := &splitter{
+		dir:      dir,
+		location: d.specFilePath,
+		rootFile: vendoredRootName(d.specFilePath),
+		files:    make(map[string]string),
+		taken:    make(map[string]struct{}),
+	}
+
+	// detach the components from the root document, and assign each a file
+	var components []splitComponent
+	for _, section := range splitSections {
+		entries, _ := root[section].(map[string]any)
+		for _, name := range slices.Sorted(maps.Keys(entries)) {
+			if section == "paths" && strings.HasPrefix(name, "x-") {
+				continue // extensions stay in the root document
+			}
+
+			component := splitComponent{
+				section: section,
+				name:    name,
+				node:    entries[name],
+				file:    s.assign("/"+section+"/"+escapePointerToken(name), section, name),
+			}
+			components = append(components, component)
+			delete(entries, name)
+		}
+	}
+
+	s.rewrite(root, s.rootFile)
+	for _, component := range components {
+		if _, inlineOnly := inlineOnlySections[component.section]; inlineOnly {
+			continue
+		}
+
+		entries, _ := root[component.section].(map[string]any)
+		entries[component.name] = map[string]any{refKey: relativeFile(s.rootFile, component.file)}
+	}
+	for section := range inlineOnlySections {
+		if entries, ok := root[section].(map[string]any); ok && len(entries) == 0 {
+			delete(root, section)
+		}
+	}
+
+	written := make([]string, 0, len(components)+1)
+	if err := s.write(s.rootFile, root); err != nil {
+		return nil, err
+	}
+	written = append(written, s.rootFile)
+
+	for _, component := range components {
+		s.rewrite(component.node, component.file)
+		if err := s.write(component.file, component.node); err != nil {
+			return nil, err
+		}
+		written = append(written, component.file)
+	}
+
+	return written, nil
+}
+
+type splitter struct {
+	dir      string
+	location string              // the location of the document, which its references to other documents are relative to
+	rootFile string              // the file of the root document, relative to dir
+	files    map[string]string   // JSON pointer of a component -> file, relative to dir
+	taken    map[string]struct{} // the file names in use, lower-cased for case-insensitive file systems
+}
+
+type splitComponent struct {
+	section string
+	name    string
+	node    any
+	file    string
+}
+
+// assign picks a free file name for the component at pointer.
+func (s *splitter) assign(pointer, section, name string) string {
+	base := name
+	if section == "paths" {
+		base = strings.NewReplacer("{", "", "}", "").Replace(strings.Trim(name, "/"))
+		if base == "" {
+			base = "root"
+		}
+	}
+	base = sanitizeFileName(base)
+
+	file := path.Join(section, base+".json")
+	for i := 2; ; i++ {
+		if _, taken := s.taken[strings.ToLower(file)]; !taken {
+			break
+		}
+		file = path.Join(section, base+"-"+strconv.Itoa(i)+".json")
+	}
+
+	s.taken[strings.ToLower(file)] = struct{}{}
+	s.files[pointer] = file
+
+	return file
+}
+
+// rewrite rewrites the references of node, to be written in file.
+func (s *splitter) rewrite(node any, file string) {
+	walkRefs(node, func(holder map[string]any, ref string) {
+		document, fragment := splitRef(ref)
+		if document != "" {
+			location := resolveDocument(s.location, document)
+			if lockKey(location) != lockKey(s.location) {
+				holder[refKey] = s.external(location, file, fragment, strings.Contains(ref, "#"))
+
+				return
+			}
+		}
+
+		holder[refKey] = s.local(fragment, file)
+	})
+}
+
+// local expresses a reference to fragment in the original document, from file.
+func (s *splitter) local(fragment, file string) string {
+	pointer := decodeFragment(fragment)
+	target, rest := s.rootFile, pointer
+
+	// a component is designated by the first two tokens of a pointer, e.g. "/definitions/Pet"
+	if tokens := strings.SplitN(pointer, "/", 4); len(tokens) >= 3 {
+		component := "/" + tokens[1] + "/" + tokens[2]
+		if split, ok := s.files[component]; ok {
+			target, rest = split, strings.TrimPrefix(pointer, component)
+		}
+	}
+
+	if target == file {
+		return "#" + encodeFragment(rest)
+	}
+
+	if rest == "" {
+		return relativeFile(file, target)
+	}
+
+	return relativeFile(file, target) + "#" + encodeFragment(rest)
+}
+
+// external expresses a reference to fragment in the document at location, from file.
+func (s *splitter) external(location, file, fragment string, withFragment bool) string {
+	target := location
+	if !isRemote(location) {
+		from, errFrom := filepath.Abs(filepath.Join(s.dir, filepath.FromSlash(path.Dir(file))))
+		to, errTo := filepath.Abs(filepath.FromSlash(strings.TrimPrefix(location, "file://")))
+		if errFrom == nil && errTo == nil {
+			if rel, err := filepath.Rel(from, to); err == nil {
+				target = filepath.ToSlash(rel)
+				if !strings.HasPrefix(target, ".") {
+					target = "./" + target
+				}
+			}
+		}
+	}
+
+	if !withFragment {
+		return target
+	}
+
+	return target + "#" + fragment
+}
+
+func (s *splitter) write(file string, node any) error {
+	out, err := json.MarshalIndent(node, "", "  ")
+	if err != nil {
+		return errLoads(err)
+	}
+
+	pth := filepath.Join(s.dir, filepath.FromSlash(file))
+	if err := os.MkdirAll(filepath.Dir(pth), 0o750); err != nil {
+		return errLoads(err)
+	}
+
+	if err := os.WriteFile(pth, append(out, '\n'), 0o600); err != nil {
+		return errLoads(err)
+	}
+
+	return nil
+}
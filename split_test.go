@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestSplit(t *testing.T) {
+	t.Run("should split a single-file spec", func(t *testing.T) {
+		document, err := Spec("testdata/json/petstore.json")
+		require.NoError(t, err)
+
+		dir := t.TempDir()
+		files, err := document.Split(dir)
+		require.NoError(t, err)
+		require.NotEmpty(t, files)
+		assert.EqualT(t, "petstore.json", files[0])
+		assert.Contains(t, files, "definitions/Pet.json")
+		assert.Contains(t, files, "paths/pets_id.json")
+
+		for _, file := range files {
+			assert.FileExists(t, filepath.Join(dir, filepath.FromSlash(file)))
+		}
+
+		split, err := Spec(filepath.Join(dir, files[0]))
+		require.NoError(t, err)
+		assert.Empty(t, split.Spec().Definitions["Pet"].Properties, "definitions should be moved out of the root document")
+		assertValidSplit(t, split)
+
+		assertSameExpansion(t, document, split)
+	})
+
+	t.Run("should rewrite references to other documents", func(t *testing.T) {
+		document, err := Spec("testdata/yaml/swagger/spec.yml")
+		require.NoError(t, err)
+
+		dir := filepath.Join(t.TempDir(), "nested", "out")
+		files, err := document.Split(dir)
+		require.NoError(t, err)
+		assert.EqualT(t, "spec.json", files[0])
+
+		split, err := Spec(filepath.Join(dir, files[0]))
+		require.NoError(t, err)
+
+		expanded, err := split.Expanded()
+		require.NoError(t, err)
+		assert.JSONMarshalAsT(t, cascadeRefExpanded, expanded.Spec())
+	})
+
+	t.Run("should pick distinct file names", func(t *testing.T) {
+		document, err := Analyzed([]byte(`{
+  "swagger": "2.0",
+  "info": {"title": "names", "version": "1"},
+  "paths": {},
+  "definitions": {
+    "a/b": {"type": "string"},
+    "a_b": {"type": "integer"},
+    "A_B": {"$ref": "#/definitions/a~1b"}
+  }
+}`), "")
+		require.NoError(t, err)
+
+		dir := t.TempDir()
+		files, err := document.Split(dir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"swagger.json", "definitions/A_B.json", "definitions/a_b-2.json", "definitions/a_b-3.json"}, files)
+
+		raw, err := os.ReadFile(filepath.Join(dir, "definitions", "A_B.json"))
+		require.NoError(t, err)
+		assert.JSONEqT(t, `{"$ref": "./a_b-2.json"}`, string(raw))
+
+		split, err := Spec(filepath.Join(dir, files[0]))
+		require.NoError(t, err)
+		assertSameExpansion(t, document, split)
+	})
+
+	t.Run("should split shared parameters and responses out of the root document", func(t *testing.T) {
+		document, err := Analyzed([]byte(`{
+  "swagger": "2.0",
+  "info": {"title": "shared", "version": "1"},
+  "parameters": {
+    "limit": {"name": "limit", "in": "query", "type": "integer"}
+  },
+  "responses": {
+    "error": {"description": "error", "schema": {"$ref": "#/definitions/Error"}}
+  },
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [{"$ref": "#/parameters/limit"}],
+        "responses": {"default": {"$ref": "#/responses/error"}}
+      }
+    }
+  },
+  "definitions": {
+    "Error": {"type": "object"}
+  }
+}`), "")
+		require.NoError(t, err)
+
+		dir := t.TempDir()
+		files, err := document.Split(dir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"swagger.json",
+			"definitions/Error.json",
+			"parameters/limit.json",
+			"responses/error.json",
+			"paths/pets.json",
+		}, files)
+
+		split, err := Spec(filepath.Join(dir, files[0]))
+		require.NoError(t, err)
+		assertValidSplit(t, split)
+		assert.Empty(t, split.Spec().Parameters)
+		assert.Empty(t, split.Spec().Responses)
+
+		raw, err := os.ReadFile(filepath.Join(dir, "paths", "pets.json"))
+		require.NoError(t, err)
+		assert.JSONEqT(t, `{
+  "get": {
+    "parameters": [{"$ref": "../parameters/limit.json"}],
+    "responses": {"default": {"$ref": "../responses/error.json"}}
+  }
+}`, string(raw))
+
+		raw, err = os.ReadFile(filepath.Join(dir, "responses", "error.json"))
+		require.NoError(t, err)
+		assert.JSONEqT(t, `{"description": "error", "schema": {"$ref": "../definitions/Error.json"}}`, string(raw))
+
+		equivalent, differences, err := Equivalent(document, split)
+		require.NoError(t, err)
+		assert.TrueT(t, equivalent, "unexpected differences: %v", differences)
+	})
+
+	t.Run("should rewrite percent-encoded local references", func(t *testing.T) {
+		document, err := Analyzed([]byte(`{
+  "swagger": "2.0",
+  "info": {"title": "encoded", "version": "1"},
+  "paths": {},
+  "definitions": {
+    "Pet": {"type": "object", "properties": {"tag": {"$ref": "#/definitions/Tag%20Name"}}},
+    "Tag Name": {"type": "object", "properties": {"label": {"type": "string"}}},
+    "Label": {"$ref": "#/definitions/Tag%20Name/properties/label"}
+  }
+}`), "")
+		require.NoError(t, err)
+
+		dir := t.TempDir()
+		_, err = document.Split(dir)
+		require.NoError(t, err)
+
+		raw, err := os.ReadFile(filepath.Join(dir, "definitions", "Pet.json"))
+		require.NoError(t, err)
+		assert.JSONEqT(t, `{"type": "object", "properties": {"tag": {"$ref": "./Tag_Name.json"}}}`, string(raw))
+
+		raw, err = os.ReadFile(filepath.Join(dir, "definitions", "Label.json"))
+		require.NoError(t, err)
+		assert.JSONEqT(t, `{"$ref": "./Tag_Name.json#/properties/label"}`, string(raw))
+	})
+}
+
+// assertValidSplit checks the root document of a split tree against the constraints of Swagger 2.0
+// on the sections it refers to other files from.
+func assertValidSplit(t *testing.T, split *Document) {
+	t.Helper()
+
+	root, err := decodeJSON(split.Raw())
+	require.NoError(t, err)
+	require.IsType(t, map[string]any{}, root)
+	sections, _ := root.(map[string]any)
+
+	parameters, _ := sections["parameters"].(map[string]any)
+	for name, node := range parameters {
+		parameter, ok := node.(map[string]any)
+		require.TrueT(t, ok, "parameter %q should be an object", name)
+		assert.NotContains(t, parameter, refKey, "parameter definition %q cannot be a reference", name)
+		assert.Contains(t, parameter, "name", "parameter definition %q should have a name", name)
+		assert.Contains(t, parameter, "in", "parameter definition %q should have a location", name)
+	}
+
+	responses, _ := sections["responses"].(map[string]any)
+	for name, node := range responses {
+		response, ok := node.(map[string]any)
+		require.TrueT(t, ok, "response %q should be an object", name)
+		assert.NotContains(t, response, refKey, "response definition %q cannot be a reference", name)
+		assert.Contains(t, response, "description", "response definition %q should have a description", name)
+	}
+}
+
+func assertSameExpansion(t *testing.T, expected, actual *Document) {
+	t.Helper()
+
+	expectedExpanded, err := expected.Expanded()
+	require.NoError(t, err)
+
+	actualExpanded, err := actual.Expanded()
+	require.NoError(t, err)
+
+	assertSameJSON(t, expectedExpanded.Spec(), actualExpanded.Spec())
+}
@@ -0,0 +1,241 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag/loading"
+)
+
+// StreamInfo describes a document opened by a [StreamLoader].
+type StreamInfo struct {
+	// Location is the location of the document, e.g. the URL after redirects.
+	Location string
+
+	// ContentType is the media type of the document, when known (e.g. "application/json").
+	ContentType string
+
+	// Size is the size of the document in bytes, or -1 when unknown.
+	Size int64
+}
+
+// StreamLoader opens a document for reading, without buffering it.
+//
+// It is the streaming counterpart of [DocLoader], used by [SpecStream]. The caller closes the
+// returned reader.
+type StreamLoader func(path string) (io.ReadCloser, StreamInfo, error)
+
+// FileStream opens a local document.
+//
+// Like [JSONDoc] without options, it applies no confinement: use [FSStream] to confine reads.
+func FileStream(pth string) (io.ReadCloser, StreamInfo, error) {
+	f, err := os.Open(filepath.FromSlash(localStreamPath(pth)))
+	if err != nil {
+		return nil, StreamInfo{}, errLoads(err)
+	}
+
+	info := StreamInfo{Location: pth, Size: -1}
+	if stat, err := f.Stat(); err == nil {
+		info.Size = stat.Size()
+	}
+
+	return f, info, nil
+}
+
+// FSStream builds a [StreamLoader] opening documents from fsys, e.g. [os.DirFS] or the file system
+// of an [os.Root]. Paths are slash-separated and relative to the root of fsys.
+func FSStream(fsys fs.FS) StreamLoader {
+	return func(pth string) (io.ReadCloser, StreamInfo, error) {
+		name := path.Clean(strings.TrimPrefix(localStreamPath(pth), "/"))
+
+		f, err := fsys.Open(name)
+		if err != nil {
+			return nil, StreamInfo{}, errLoads(err)
+		}
+
+		info := StreamInfo{Location: pth, Size: -1}
+		if stat, err := f.Stat(); err == nil {
+			info.Size = stat.Size()
+		}
+
+		return f, info, nil
+	}
+}
+
+// localStreamPath yields the local path designated by a plain path or a "file://" URI, as references
+// resolve to the latter.
+func localStreamPath(pth string) string {
+	if !strings.HasPrefix(pth, "file://") {
+		return pth
+	}
+
+	u, err := url.Parse(pth)
+	if err != nil {
+		return strings.TrimPrefix(pth, "file://")
+	}
+
+	return u.Path
+}
+
+// HTTPStream builds a [StreamLoader] fetching remote documents with client, or with
+// [http.DefaultClient] if client is nil. Use [RestrictedHTTPClient] for untrusted locations.
+func HTTPStream(client *http.Client) StreamLoader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return func(pth string) (io.ReadCloser, StreamInfo, error) {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, pth, nil)
+		if err != nil {
+			return nil, StreamInfo{}, errLoads(err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, StreamInfo{}, errLoads(err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			_ = resp.Body.Close()
+
+			return nil, StreamInfo{}, fmt.Errorf("%w: could not access document at %q [%s]", ErrLoads, pth, resp.Status)
+		}
+
+		return resp.Body, StreamInfo{
+			Location:    resp.Request.URL.String(),
+			ContentType: resp.Header.Get("Content-Type"),
+			Size:        resp.ContentLength,
+		}, nil
+	}
+}
+
+// SpecStream loads a new spec document from a local or remote path, like [Spec], but reads it as a
+// stream opened by open, e.g. [FileStream], [FSStream] or [HTTPStream].
+//
+// The document is read like with [AnalyzedStream], and located where open reports it to be, e.g.
+// after HTTP redirects. Its references are loaded with open as well (see [StreamLoaders]), unless
+// opts configure other loaders.
+func SpecStream(pth string, open StreamLoader, opts ...LoaderOption) (*Document, error) {
+	r, info, err := open(pth)
+	if err != nil {
+		return nil, errLoads(err)
+	}
+	defer r.Close()
+
+	opts = append([]LoaderOption{WithDocLoaderMatches(StreamLoaders(open)...)}, opts...)
+	document, err := AnalyzedStream(r, "", opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	document.specFilePath = pth
+	if info.Location != "" {
+		document.specFilePath = info.Location
+	}
+
+	return document, nil
+}
+
+// StreamLoaders builds a chain of loaders reading documents with open, e.g. to resolve references
+// with [WithDocLoaderMatches]. Like with [DefaultLoaders], YAML documents are converted to JSON.
+//
+// Referenced documents are read in full. The loading options are not passed to open, which applies
+// its own confinement, if any.
+func StreamLoaders(open StreamLoader) []DocLoaderWithMatch {
+	fetch := func(pth string, _ ...loading.Option) (json.RawMessage, error) {
+		r, _, err := open(pth)
+		if err != nil {
+			return nil, errLoads(err)
+		}
+		defer r.Close()
+
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, errLoads(err)
+		}
+
+		return data, nil
+	}
+
+	return []DocLoaderWithMatch{
+		newFetchingDocLoader(fetch, yamlToJSON, loading.YAMLMatcher),
+		newFetchingDocLoader(fetch, asJSON, nil),
+	}
+}
+
+// AnalyzedStream creates a new analyzed spec document, like [Analyzed], for a document read from r.
+//
+// JSON input is decoded into the spec as it is read, without first holding the document as read, nor
+// cloning the decoded spec. The source of the document is encoded back from the decoded spec, like with
+// [NewDocument]: [Document.Raw] and [Document.SourceRaw] yield that encoding rather than the bytes read.
+// A JSON document is read up to the end of its first value, and any data after it is rejected.
+//
+// YAML input is read until EOF, and handled like with [Analyzed].
+func AnalyzedStream(r io.Reader, version string, options ...LoaderOption) (*Document, error) {
+	br := bufio.NewReader(r)
+	if !startsWithJSON(br) {
+		data, err := io.ReadAll(br)
+		if err != nil {
+			return nil, errLoads(err)
+		}
+
+		return Analyzed(data, version, options...)
+	}
+
+	if version == "" {
+		version = "2.0"
+	}
+	if version != "2.0" {
+		return nil, fmt.Errorf("%w: spec version %q is not supported", ErrLoads, version)
+	}
+
+	dec := json.NewDecoder(br)
+	swspec := new(spec.Swagger)
+	if err := dec.Decode(swspec); err != nil {
+		return nil, errLoads(err)
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: unexpected data after the JSON document", ErrLoads)
+	}
+
+	return decodedDocument(swspec, options...)
+}
+
+// startsWithJSON tells if the first non-blank character read from br opens a JSON object.
+func startsWithJSON(br *bufio.Reader) bool {
+	for {
+		c, err := br.ReadByte()
+		if err != nil {
+			return false
+		}
+
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '{':
+			_ = br.UnreadByte()
+
+			return true
+		default:
+			_ = br.UnreadByte()
+
+			return false
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestSpecStream(t *testing.T) {
+	const todos = "todos.json"
+	dir := filepath.Join("testdata", "bugs", "145", "Program Files (x86)", "AppName")
+
+	buffered, err := Spec(filepath.Join(dir, todos))
+	require.NoError(t, err)
+	expected, err := buffered.Expanded()
+	require.NoError(t, err)
+
+	t.Run("should stream a local document", func(t *testing.T) {
+		document, err := SpecStream(filepath.Join(dir, todos), FileStream)
+		require.NoError(t, err)
+		assert.EqualT(t, filepath.Join(dir, todos), document.SpecFilePath())
+		assertSameJSON(t, buffered.Spec(), document.Spec())
+
+		expanded, err := document.Expanded()
+		require.NoError(t, err)
+		assertSameJSON(t, expected.Spec(), expanded.Spec())
+	})
+
+	t.Run("should stream from a file system", func(t *testing.T) {
+		document, err := SpecStream(todos, FSStream(os.DirFS(dir)))
+		require.NoError(t, err)
+		assertSameJSON(t, buffered.Spec(), document.Spec())
+
+		_, err = SpecStream("../"+todos, FSStream(os.DirFS(dir)))
+		require.ErrorIs(t, err, ErrLoads)
+	})
+
+	t.Run("should stream a remote document", func(t *testing.T) {
+		srv := httptest.NewServer(http.FileServer(http.Dir(dir)))
+		defer srv.Close()
+
+		r, info, err := HTTPStream(nil)(srv.URL + "/" + todos)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		assert.EqualT(t, "application/json", info.ContentType)
+		assert.Positive(t, info.Size)
+
+		document, err := SpecStream(srv.URL+"/"+todos, HTTPStream(srv.Client()))
+		require.NoError(t, err)
+
+		expanded, err := document.Expanded()
+		require.NoError(t, err)
+		assertSameJSON(t, expected.Spec(), expanded.Spec())
+
+		_, err = SpecStream(srv.URL+"/missing.json", HTTPStream(srv.Client()))
+		require.ErrorIs(t, err, ErrLoads)
+	})
+
+	t.Run("should load references with the stream loader", func(t *testing.T) {
+		var opened []string
+		open := func(pth string) (io.ReadCloser, StreamInfo, error) {
+			opened = append(opened, pth)
+
+			return FileStream(pth)
+		}
+
+		document, err := SpecStream(filepath.Join(dir, todos), open)
+		require.NoError(t, err)
+
+		expanded, err := document.Expanded()
+		require.NoError(t, err)
+		assertSameJSON(t, expected.Spec(), expanded.Spec())
+		require.Len(t, opened, 3)
+		assert.StringContainsT(t, opened[1], "ref.json")
+		assert.StringContainsT(t, opened[2], "todos.common.json")
+	})
+
+	t.Run("should locate the document where the stream loader reports it", func(t *testing.T) {
+		moved := filepath.Join(dir, todos)
+		open := func(string) (io.ReadCloser, StreamInfo, error) {
+			r, info, err := FileStream(moved)
+			info.Location = moved
+
+			return r, info, err
+		}
+
+		document, err := SpecStream("https://example.com/todos.json", open)
+		require.NoError(t, err)
+		assert.EqualT(t, moved, document.SpecFilePath())
+	})
+
+	t.Run("should fail on a missing document", func(t *testing.T) {
+		_, err := SpecStream(filepath.Join(dir, "missing.json"), FileStream)
+		require.ErrorIs(t, err, ErrLoads)
+	})
+}
+
+func TestAnalyzedStream(t *testing.T) {
+	t.Run("should keep the source apart from the current spec", func(t *testing.T) {
+		document, err := AnalyzedStream(bytes.NewReader(petStoreJSON), "")
+		require.NoError(t, err)
+		assert.EqualT(t, "petstore.swagger.wordnik.com", document.Host())
+
+		assert.JSONEqT(t, string(petStoreJSON), string(document.Raw()))
+		assert.Equal(t, document.Raw(), document.SourceRaw())
+		assert.NotSame(t, document.Spec(), document.OrigSpec())
+
+		document.Spec().Host = "altered.example.com"
+		assert.EqualT(t, "petstore.swagger.wordnik.com", document.OrigSpec().Host)
+		assert.NotContains(t, string(document.SourceRaw()), "altered.example.com")
+
+		reset := document.ResetDefinitions()
+		assert.Len(t, reset.Spec().Definitions, len(document.Spec().Definitions))
+	})
+
+	t.Run("should compute the source once for concurrent readers", func(t *testing.T) {
+		document, err := AnalyzedStream(bytes.NewReader(petStoreJSON), "")
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Go(func() {
+				_ = document.Raw()
+				_ = document.OrigSpec()
+				_ = document.Pristine().SourceRaw()
+			})
+		}
+		wg.Wait()
+	})
+
+	t.Run("should buffer YAML input", func(t *testing.T) {
+		document, err := AnalyzedStream(bytes.NewReader(yamlSpec), "")
+		require.NoError(t, err)
+
+		buffered, err := Analyzed(yamlSpec, "")
+		require.NoError(t, err)
+		assert.Equal(t, buffered.Raw(), document.Raw())
+	})
+
+	t.Run("should reject invalid input", func(t *testing.T) {
+		_, err := AnalyzedStream(strings.NewReader(`  {"swagger": `), "")
+		require.ErrorIs(t, err, ErrLoads)
+
+		_, err = AnalyzedStream(bytes.NewReader(petStoreJSON), "3.0")
+		require.ErrorIs(t, err, ErrLoads)
+	})
+
+	t.Run("should reject data after the JSON document", func(t *testing.T) {
+		_, err := AnalyzedStream(bytes.NewReader(append(bytes.Clone(petStoreJSON), " \n"...)), "")
+		require.NoError(t, err)
+
+		for _, trailing := range []string{`{}`, `garbage`, `}`} {
+			_, err = AnalyzedStream(bytes.NewReader(append(bytes.Clone(petStoreJSON), trailing...)), "")
+			require.ErrorIs(t, err, ErrLoads)
+		}
+	})
+}
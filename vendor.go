@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// vendoredRefsDir is the subdirectory of the vendoring directory holding the dependencies of the root document.
+const vendoredRefsDir = "refs"
+
+// Vendor writes a snapshot of the document and of every document it references, directly or
+// transitively, into dir.
+//
+// Every dependency is fetched once through the document's loader (so [WithRefMapping], [WithOffline]
+// and confinement options apply). The root document is written as held by d, at the top of dir, and
+// its dependencies under dir/refs. Documents are written as JSON, with their "$ref"s rewritten as relative paths
+// into the vendored tree, so that loading and expanding the vendored root requires no network
+// access and yields the same result as the original.
+//
+// A lockfile named [LockfileName], listing the original location of every document with the
+// SHA-256 of its content as fetched, i.e. of the very bytes it is vendored from, is written into dir
+// and returned.
+func (d *Document) Vendor(dir string) (*Lockfile, error) {
+	v := &vendorer{
+		files: make(map[string]string),
+		lock:  &Lockfile{Version: lockfileVersion},
+	}
+
+	rootFile := vendoredRootName(d.specFilePath)
+	rootKey := lockKey(d.specFilePath)
+	v.files[rootKey] = rootFile
+
+	// the root is vendored as held by the document, and pinned as it was fetched when it was loaded
+	root := vendoredDocument{location: d.specFilePath, key: rootKey, raw: d.Raw(), fetched: d.source.fetchedRaw()}
+	if d.specFilePath != "" {
+		v.lock.Root = rootKey
+	}
+
+	documents := []vendoredDocument{root}
+	err := d.walkDependencies(func(location string, fetched fetchedDocument) error {
+		key := lockKey(location)
+		v.files[key] = path.Join(vendoredRefsDir, vendoredName(key))
+		documents = append(documents, vendoredDocument{location: location, key: key, raw: fetched.json, fetched: fetched.raw})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, vendoredRefsDir), 0o750); err != nil {
+		return nil, errLoads(err)
+	}
+
+	for _, document := range documents {
+		if err := v.vendor(dir, document); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := v.lock.WriteFile(filepath.Join(dir, LockfileName)); err != nil {
+		return nil, err
+	}
+
+	return v.lock, nil
+}
+
+type vendorer struct {
+	files map[string]string // lock key -> vendored path, relative to the vendoring directory
+	lock  *Lockfile
+}
+
+type vendoredDocument struct {
+	location string
+	key      string
+	raw      json.RawMessage // the document as JSON
+	fetched  []byte          // the document as fetched, which its pinned hash covers
+}
+
+// vendor rewrites the references of a single document to the vendored files, and writes it.
+func (v *vendorer) vendor(dir string, current vendoredDocument) error {
+	node, err := decodeJSON(current.raw)
+	if err != nil {
+		return errLoads(fmt.Errorf("vendoring %q: %w", current.location, err))
+	}
+
+	target := v.files[current.key]
+	walkRefs(node, func(holder map[string]any, ref string) {
+		document, fragment := splitRef(ref)
+		if document == "" {
+			return // local reference: left untouched
+		}
+
+		file, known := v.files[lockKey(resolveDocument(current.location, document))]
+		if !known {
+			return
+		}
+
+		rel := relativeFile(target, file)
+		if strings.Contains(ref, "#") {
+			rel += "#" + fragment
+		}
+		holder[refKey] = rel
+	})
+
+	out, err := json.MarshalIndent(node, "", "  ")
+	if err != nil {
+		return errLoads(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(target)), append(out, '\n'), 0o600); err != nil {
+		return errLoads(err)
+	}
+
+	// the hash covers the document as fetched, like an IntegrityVerifier
+	v.lock.set(LockEntry{URL: current.key, Path: target, SHA256: hashOf(current.fetched)})
+
+	return nil
+}
+
+// vendoredRootName yields the file name of the vendored root document.
+func vendoredRootName(location string) string {
+	name := strings.TrimSuffix(path.Base(filepath.ToSlash(location)), path.Ext(location))
+	if name == "" || name == "." || name == "/" {
+		name = "swagger"
+	}
+
+	return sanitizeFileName(name) + ".json"
+}
+
+// vendoredName yields a stable, collision-free file name for a dependency, derived from its location.
+func vendoredName(key string) string {
+	const hashPrefixLen = 12
+
+	u := strings.TrimSuffix(key, "/")
+	name := strings.TrimSuffix(path.Base(u), path.Ext(u))
+
+	return sanitizeFileName(name) + "-" + hashOf([]byte(key))[:hashPrefixLen] + ".json"
+}
+
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// relativeFile expresses the slash-separated path target relative to the directory of from.
+func relativeFile(from, target string) string {
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(from)), filepath.FromSlash(target))
+	if err != nil {
+		return target
+	}
+
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, ".") {
+		rel = "./" + rel
+	}
+
+	return rel
+}
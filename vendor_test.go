@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loads
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestVendor(t *testing.T) {
+	t.Run("should vendor a local spec with its dependencies", func(t *testing.T) {
+		document, err := Spec("testdata/yaml/swagger/spec.yml")
+		require.NoError(t, err)
+
+		dir := t.TempDir()
+		lock, err := document.Vendor(dir)
+		require.NoError(t, err)
+		require.Len(t, lock.Documents, 2)
+		assert.EqualT(t, lockKey("testdata/yaml/swagger/spec.yml"), lock.Root)
+
+		for _, entry := range lock.Documents {
+			b, err := os.ReadFile(filepath.FromSlash(entry.URL))
+			require.NoError(t, err)
+			assert.EqualT(t, hashOf(b), entry.SHA256, "the hash should match the document as fetched")
+			assert.FileExists(t, filepath.Join(dir, filepath.FromSlash(entry.Path)))
+		}
+
+		onDisk, err := ReadLockfile(filepath.Join(dir, LockfileName))
+		require.NoError(t, err)
+		assert.Equal(t, lock, onDisk)
+
+		vendored, err := Spec(filepath.Join(dir, "spec.json"), WithOffline())
+		require.NoError(t, err)
+
+		expanded, err := vendored.Expanded()
+		require.NoError(t, err)
+		assert.JSONMarshalAsT(t, cascadeRefExpanded, expanded.Spec())
+	})
+
+	t.Run("should vendor a spec with nested remote references and expand it offline", func(t *testing.T) {
+		srv := httptest.NewServer(http.FileServer(http.Dir(filepath.Join("testdata", "bugs", "145", "Program Files (x86)", "AppName"))))
+		document, err := Spec(srv.URL + "/todos.json")
+		require.NoError(t, err)
+
+		online, err := document.Expanded()
+		require.NoError(t, err)
+
+		dir := t.TempDir()
+		lock, err := document.Vendor(dir)
+		require.NoError(t, err)
+		srv.Close()
+
+		assert.EqualT(t, srv.URL+"/todos.json", lock.Root)
+		for _, entry := range lock.Documents {
+			assert.FileExists(t, filepath.Join(dir, filepath.FromSlash(entry.Path)))
+		}
+
+		vendored, err := Spec(filepath.Join(dir, "todos.json"), WithOffline())
+		require.NoError(t, err)
+
+		offline, err := vendored.Expanded()
+		require.NoError(t, err)
+		want, err := json.Marshal(online.Spec())
+		require.NoError(t, err)
+		assert.JSONMarshalAsT(t, want, offline.Spec())
+	})
+
+	t.Run("should fetch each dependency once, and pin the bytes it vendors", func(t *testing.T) {
+		var requests atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/spec.json":
+				_, _ = w.Write([]byte(`{"swagger":"2.0","info":{"title":"t","version":"1"},"paths":{},` +
+					`"definitions":{"a":{"$ref":"models.json#/definitions/a"}}}`))
+			case "/models.json":
+				// the document changes at every request
+				_, _ = fmt.Fprintf(w, `{"definitions":{"a":{"type":"string","description":"%d"}}}`, requests.Add(1))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer srv.Close()
+
+		document, err := Spec(srv.URL + "/spec.json")
+		require.NoError(t, err)
+
+		dir := t.TempDir()
+		lock, err := document.Vendor(dir)
+		require.NoError(t, err)
+		assert.EqualT(t, int32(1), requests.Load())
+
+		entry, ok := lock.Lookup(srv.URL + "/models.json")
+		require.TrueT(t, ok)
+		assert.EqualT(t, hashOf([]byte(`{"definitions":{"a":{"type":"string","description":"1"}}}`)), entry.SHA256)
+
+		vendored, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(entry.Path)))
+		require.NoError(t, err)
+		assert.JSONEqT(t, `{"definitions":{"a":{"type":"string","description":"1"}}}`, string(vendored))
+	})
+
+	t.Run("should vendor the root document as loaded", func(t *testing.T) {
+		pth := filepath.Join(t.TempDir(), "spec.yaml")
+		loaded := []byte("swagger: \"2.0\"\ninfo: {title: loaded, version: \"1\"}\npaths: {}\n")
+		require.NoError(t, os.WriteFile(pth, loaded, 0o600))
+
+		document, err := Spec(pth)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(pth, []byte("swagger: \"2.0\"\ninfo: {title: changed, version: \"1\"}\npaths: {}\n"), 0o600))
+
+		dir := t.TempDir()
+		lock, err := document.Vendor(dir)
+		require.NoError(t, err)
+
+		vendored, err := os.ReadFile(filepath.Join(dir, "spec.json"))
+		require.NoError(t, err)
+		assert.JSONEqT(t, string(document.Raw()), string(vendored))
+
+		entry, ok := lock.Lookup(pth)
+		require.TrueT(t, ok)
+		assert.EqualT(t, hashOf(loaded), entry.SHA256, "the root should be pinned as fetched when it was loaded")
+	})
+
+	t.Run("should fail when a dependency cannot be loaded", func(t *testing.T) {
+		pth := filepath.Join(t.TempDir(), "spec.json")
+		require.NoError(t, os.WriteFile(pth, []byte(`{"swagger":"2.0","info":{"title":"t","version":"1"},"paths":{},`+
+			`"definitions":{"a":{"$ref":"missing.json#/definitions/a"}}}`), 0o600))
+
+		document, err := Spec(pth)
+		require.NoError(t, err)
+
+		_, err = document.Vendor(t.TempDir())
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoads)
+	})
+}
+
+func TestRelativeFile(t *testing.T) {
+	assert.EqualT(t, "./refs/a.json", relativeFile("spec.json", "refs/a.json"))
+	assert.EqualT(t, "./b.json", relativeFile("refs/a.json", "refs/b.json"))
+	assert.EqualT(t, "../spec.json", relativeFile("refs/a.json", "spec.json"))
+}